@@ -3,6 +3,8 @@ module github.com/gerifield/mnb-qr-go
 go 1.14
 
 require (
+	github.com/makiuchi-d/gozxing v0.1.1
 	github.com/skip2/go-qrcode v0.0.0-20200519171959-a3b48390827e
 	github.com/stretchr/testify v1.7.0
+	github.com/ulikunitz/xz v0.5.12
 )
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// convertCmd converts an MNB code to EPC or an EPC code to MNB, the payload is read from the file argument or the stdin
+func convertCmd(args []string) error {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	kindName := fs.String("kind", "HCT", "MNB code type of the EPC conversion (RTP/HCT)")
+	out := fs.String("out", "", "Write the converted code into this PNG file")
+	_ = fs.Parse(args)
+
+	var content []byte
+	var err error
+	if fs.NArg() > 0 && fs.Arg(0) != "-" {
+		content, err = ioutil.ReadFile(fs.Arg(0))
+	} else {
+		content, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, payload, err := qr.Detect(string(content))
+	if err != nil {
+		return err
+	}
+
+	var converted qr.Payload
+	var losses []qr.Loss
+	target := qr.KindEPC.String()
+	switch p := payload.(type) {
+	case *qr.Code:
+		converted, losses, err = p.ToEPC()
+	case *qr.EPCCode:
+		k := qr.KindHCT
+		switch strings.ToUpper(*kindName) {
+		case "HCT":
+		case "RTP":
+			k = qr.KindRTP
+		default:
+			return fmt.Errorf("invalid kind (should be RTP or HCT)")
+		}
+
+		var c *qr.Code
+		target = k.String()
+		c, losses, err = p.ToMNB(k)
+		if err == nil {
+			err = c.ValidUntil(time.Now().Add(2 * time.Hour))
+		}
+		converted = c
+	default:
+		return fmt.Errorf("only MNB and EPC codes could be converted")
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, l := range losses {
+		_, _ = fmt.Fprintln(os.Stderr, "lost:", l)
+	}
+	fmt.Println(converted.String())
+
+	if *out == "" {
+		return nil
+	}
+
+	format, _ := qr.LookupFormat(target)
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return qr.WritePNG(f, format, converted, qr.RenderOptions{Size: 256})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/gerifield/mnb-qr-go/src/einvoice"
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// fromEInvoiceCmd creates an HCT code from an UBL or CII e-invoice, the not mapped fields are printed to the stderr
+func fromEInvoiceCmd(args []string) error {
+	fs := flag.NewFlagSet("from-einvoice", flag.ExitOnError)
+	bic := fs.String("bic", "", "BIC of the payee's bank if the invoice does not contain it")
+	out := fs.String("out", "", "Write the code into this PNG file")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: mnb-qr-gen from-einvoice [-bic BIC] [-out code.png] invoice.xml")
+	}
+
+	b, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	from := einvoice.FromUBL
+	if bytes.Contains(b, []byte("CrossIndustryInvoice")) {
+		from = einvoice.FromCII
+	}
+
+	c, losses, err := from(b, *bic)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fs.Arg(0), err)
+	}
+
+	for _, l := range losses {
+		_, _ = fmt.Fprintln(os.Stderr, "not mapped:", l)
+	}
+	fmt.Println(c.String())
+	return writeHCT(*out, c)
+}
+
+// writeHCT writes the code as PNG if the file name is set
+func writeHCT(out string, c *qr.Code) error {
+	if out == "" {
+		return nil
+	}
+
+	format, _ := qr.LookupFormat(qr.KindHCT.String())
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return qr.WritePNG(f, format, c, qr.RenderOptions{Size: 256})
+}
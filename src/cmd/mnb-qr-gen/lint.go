@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// lintCmd checks an MNB payload from the file argument or the stdin, it fails if there is an error finding
+func lintCmd(args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	var content []byte
+	var err error
+	if fs.NArg() > 0 && fs.Arg(0) != "-" {
+		content, err = ioutil.ReadFile(fs.Arg(0))
+	} else {
+		content, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return err
+	}
+
+	findings := qr.Check(string(content))
+	for _, f := range findings {
+		fmt.Println(f)
+	}
+
+	if errs := qr.Errors(findings); len(errs) > 0 {
+		return fmt.Errorf("%d errors, %d warnings", len(errs), len(findings)-len(errs))
+	}
+	return nil
+}
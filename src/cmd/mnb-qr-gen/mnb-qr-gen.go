@@ -11,67 +11,129 @@ import (
 	"github.com/gerifield/mnb-qr-go/src/qr"
 )
 
-func main() {
-	qrType := flag.String("type", "RTP", "QR code type (RTP/HCT)")
-	bic := flag.String("bic", "", "BIC code")
-	name := flag.String("name", "", "Name")
-	iban := flag.String("iban", "", "IBAN number")
-	amount := flag.Int("amount", 0, "Amount to request (in HUF)")
-	message := flag.String("message", "", "Message in the QR code")
-	flag.Parse()
+// Sub commands, without any the tool generates a code
+var commands = map[string]func(args []string) error{
+	"purposes":      purposesCmd,
+	"convert":       convertCmd,
+	"ndef":          ndefCmd,
+	"pain001":       pain001Cmd,
+	"reconcile":     reconcileCmd,
+	"from-nav":      fromNAVCmd,
+	"from-einvoice": fromEInvoiceCmd,
+	"lint":          lintCmd,
+}
 
-	qrt := strings.ToUpper(*qrType)
-	if qrt != "RTP" && qrt != "HCT" {
-		fmt.Println("Invalid QR code type (shoulb be RTP or HCT)")
-		os.Exit(1)
+// validUntil is implemented by the formats with expiration
+type validUntil interface {
+	ValidUntil(t time.Time) error
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			return
+		}
 	}
 
-	var err error
-	var code *qr.Code
-	if qrt == "HCT" {
-		code, err = qr.NewPaymentSend(*bic, *name, *iban)
-	} else {
-		code, err = qr.NewPaymentRequest(*bic, *name, *iban)
+	qrType := flag.String("type", "RTP", "QR code type ("+strings.Join(qr.FormatNames(), "/")+")")
+	docs := flag.Bool("fields", false, "Print the field documentation of the type and exit")
+
+	// Every other field flag comes from the field lists of the formats
+	values := make(map[string]*string)
+	for _, name := range qr.FormatNames() {
+		format, _ := qr.LookupFormat(name)
+		for _, f := range format.Fields() {
+			switch f.Name {
+			case "kind", "valid", "serviceTag", "identification": // Set by the type flag, the fixed expiration or constants
+				continue
+			}
+			if _, ok := values[f.Name]; ok {
+				continue
+			}
+			values[f.Name] = flag.String(f.Name, "", fmt.Sprintf("%s (%d chars max)", f.Usage, f.MaxLen))
+		}
 	}
-	if err != nil {
-		fmt.Println(err)
+	flag.Parse()
+
+	format, ok := qr.LookupFormat(*qrType)
+	if !ok {
+		fmt.Printf("Invalid QR code type (shoulb be one of %s)\n", strings.Join(qr.FormatNames(), ", "))
 		os.Exit(1)
 	}
 
-	err = code.HUFAmount(*amount)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if *docs {
+		_ = qr.WriteFieldDocs(os.Stdout, format.Fields())
+		return
 	}
 
-	err = code.Message(*message)
+	// Only the explicitly set flags are used
+	set := make(map[string]string)
+	flag.Visit(func(f *flag.Flag) {
+		if v, ok := values[f.Name]; ok {
+			set[f.Name] = *v
+		}
+	})
+
+	c := format.New()
+	err := setFields(format.Fields(), c.Set, set, format.Name())
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	_ = code.ValidUntil(time.Now().Add(2 * time.Hour))
+	if v, ok := c.(validUntil); ok {
+		_ = v.ValidUntil(time.Now().Add(2 * time.Hour))
+	}
 
-	fmt.Println(code.String())
-	png, err := code.GeneratePNG(256)
+	fmt.Println(c.String())
+	f, err := os.Create("out.png")
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	defer f.Close()
 
-	f, err := os.Create("out.png")
+	err = qr.WritePNG(f, format, c, qr.RenderOptions{Size: 256})
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	defer f.Close()
-	_, _ = f.Write(png)
 
 	// Open the image
-	c := exec.Command("open", "out.png")
-	err = c.Run()
+	cmd := exec.Command("open", "out.png")
+	err = cmd.Run()
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 }
+
+// setFields sets the values on the code, values without a field are not supported by the kind
+func setFields(fields []qr.Field, set func(name, value string) error, values map[string]string, kindName string) error {
+	known := make(map[string]bool)
+	for _, f := range fields {
+		known[f.Name] = true
+	}
+
+	for name := range values {
+		if !known[name] {
+			return fmt.Errorf("%s is not supported by %s", name, kindName)
+		}
+	}
+
+	for _, f := range fields {
+		value, ok := values[f.Name]
+		if !ok {
+			continue
+		}
+
+		if err := set(f.Name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
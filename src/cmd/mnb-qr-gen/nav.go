@@ -0,0 +1,39 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/gerifield/mnb-qr-go/src/einvoice"
+)
+
+// fromNAVCmd creates an HCT code from a NAV Online Számla 3.0 invoice XML, the not mapped fields are printed to the stderr
+func fromNAVCmd(args []string) error {
+	fs := flag.NewFlagSet("from-nav", flag.ExitOnError)
+	bic := fs.String("bic", "", "BIC of the supplier's bank, it's not part of the invoice")
+	out := fs.String("out", "", "Write the code into this PNG file")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: mnb-qr-gen from-nav -bic BIC [-out code.png] invoice.xml")
+	}
+
+	b, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	c, losses, err := einvoice.FromNAV(b, *bic)
+	if err != nil {
+		return fmt.Errorf("%s: %v", fs.Arg(0), err)
+	}
+
+	for _, l := range losses {
+		_, _ = fmt.Fprintln(os.Stderr, "not mapped:", l)
+	}
+	fmt.Println(c.String())
+	return writeHCT(*out, c)
+}
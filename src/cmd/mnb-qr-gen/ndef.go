@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/gerifield/mnb-qr-go/src/ndef"
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// ndefCmd wraps a payment code payload into an NDEF message or decodes a written one
+// The input is read from the file argument or the stdin.
+func ndefCmd(args []string) error {
+	fs := flag.NewFlagSet("ndef", flag.ExitOnError)
+	decode := fs.Bool("decode", false, "Decode an NDEF message instead of creating one")
+	hexFormat := fs.Bool("hex", false, "Use hex instead of binary for the NDEF message")
+	uri := fs.String("uri", "", "URI record added before the payment record")
+	out := fs.String("out", "", "Write the NDEF message into this file instead of the stdout")
+	_ = fs.Parse(args)
+
+	var input []byte
+	var err error
+	if fs.NArg() > 0 && fs.Arg(0) != "-" {
+		input, err = ioutil.ReadFile(fs.Arg(0))
+	} else {
+		input, err = ioutil.ReadAll(os.Stdin)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *decode {
+		if *hexFormat {
+			input, err = hex.DecodeString(strings.TrimSpace(string(input)))
+			if err != nil {
+				return err
+			}
+		}
+
+		f, p, err := ndef.ParsePayment(input)
+		if err != nil {
+			return err
+		}
+		fmt.Println(f.Name())
+		fmt.Println(p.String())
+		return nil
+	}
+
+	f, p, err := qr.Detect(string(input))
+	if err != nil {
+		return err
+	}
+
+	m, err := ndef.PaymentMessage(f, p, *uri)
+	if err != nil {
+		return err
+	}
+
+	b, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if *hexFormat {
+		b = []byte(strings.ToUpper(hex.EncodeToString(b)) + "\n")
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(*out, b, 0644)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/iso20022"
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// pain001Cmd exports the HCT code payload files (one code per file) to a pain.001 XML
+func pain001Cmd(args []string) error {
+	fs := flag.NewFlagSet("pain001", flag.ExitOnError)
+	id := fs.String("id", "", "Message ID (35 chars max)")
+	version := fs.String("version", "03", "pain.001 version (03/09)")
+	execution := fs.String("execution", "", "Requested execution date in 2006-01-02 format, today by default")
+	name := fs.String("debtor-name", "", "Payer name")
+	iban := fs.String("debtor-iban", "", "Payer IBAN")
+	bic := fs.String("debtor-bic", "", "Payer BIC")
+	out := fs.String("out", "", "Write the XML into this file instead of the stdout")
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		return errors.New("at least one code file is required")
+	}
+
+	v, err := iso20022.LookupPain001Version(*version)
+	if err != nil {
+		return err
+	}
+
+	batch := iso20022.Batch{
+		MessageID: *id,
+		Version:   v,
+		Debtor:    iso20022.Party{Name: *name, IBAN: *iban, BIC: *bic},
+	}
+	if *execution != "" {
+		batch.Execution, err = time.Parse("2006-01-02", *execution)
+		if err != nil {
+			return errors.New("invalid execution date")
+		}
+	}
+
+	transfers := make([]iso20022.Transfer, 0, fs.NArg())
+	for _, file := range fs.Args() {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		c, err := qr.Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		transfers = append(transfers, iso20022.Transfer{Code: *c})
+	}
+
+	b, err := iso20022.Pain001(transfers, batch)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return ioutil.WriteFile(*out, b, 0644)
+}
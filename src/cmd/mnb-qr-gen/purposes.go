@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// purposesCmd lists the purpose codes, the arguments are used as a search keyword
+func purposesCmd(args []string) error {
+	fs := flag.NewFlagSet("purposes", flag.ExitOnError)
+	hungarian := fs.Bool("hu", false, "Show the Hungarian names")
+	_ = fs.Parse(args)
+
+	purposes := qr.SearchPurposes(strings.Join(fs.Args(), " "))
+	if len(purposes) == 0 {
+		return fmt.Errorf("no purpose code found")
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, p := range purposes {
+		name := p.Name
+		if *hungarian {
+			name = p.NameHU
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", p.Code, p.Category, name)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+	"github.com/gerifield/mnb-qr-go/src/reconcile"
+)
+
+// reconcileCmd matches the issued code payload files (one code per file) with the transactions of a statement
+func reconcileCmd(args []string) error {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	statement := fs.String("statement", "", "statement file")
+	format := fs.String("format", "camt", "statement format (camt, mt940 or csv)")
+	mapping := fs.String("csv-mapping", "", "JSON file with the column mapping of the csv statement")
+	_ = fs.Parse(args)
+
+	if *statement == "" {
+		return errors.New("statement is required")
+	}
+
+	b, err := ioutil.ReadFile(*statement)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := parseStatement(b, *format, *mapping)
+	if err != nil {
+		return err
+	}
+
+	codes := make([]qr.Code, 0, fs.NArg())
+	for _, file := range fs.Args() {
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		c, err := qr.Parse(string(content))
+		if err != nil {
+			return fmt.Errorf("%s: %v", file, err)
+		}
+		codes = append(codes, *c)
+	}
+
+	report := reconcile.Reconcile(codes, transactions)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for i, m := range report.Matches {
+		var refs []string
+		for _, tx := range m.Transactions {
+			refs = append(refs, tx.Reference)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", m.Status, fs.Arg(i), m.Code.Get("amount"), formatHundredths(m.Paid), strings.Join(refs, ","))
+	}
+
+	for _, tx := range report.Unmatched {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t\t%s\t%s\n", "no code", tx.DebtorName, formatHundredths(tx.Amount), tx.Reference)
+	}
+	return tw.Flush()
+}
+
+func parseStatement(b []byte, format string, mapping string) ([]reconcile.Transaction, error) {
+	switch format {
+	case "camt":
+		return reconcile.ParseCAMT(b)
+	case "mt940":
+		return reconcile.ParseMT940(b)
+	case "csv":
+		if mapping == "" {
+			return nil, errors.New("csv-mapping is required")
+		}
+
+		content, err := ioutil.ReadFile(mapping)
+		if err != nil {
+			return nil, err
+		}
+
+		var m reconcile.CSVMapping
+		if err := json.Unmarshal(content, &m); err != nil {
+			return nil, err
+		}
+		return reconcile.ParseCSV(bytes.NewReader(b), m)
+	}
+	return nil, fmt.Errorf("unknown statement format: %s", format)
+}
+
+func formatHundredths(v int) string {
+	return fmt.Sprintf("%d.%02d", v/100, v%100)
+}
@@ -15,6 +15,10 @@ func main() {
 	s := server.New()
 
 	http.HandleFunc("/", s.GenerateHandler)
+	http.HandleFunc("/purposes", s.PurposesHandler)
+	http.HandleFunc("/pain001", s.Pain001Handler)
+	http.HandleFunc("/templates", s.TemplateHandler)
+	http.HandleFunc("/templates/code", s.TemplateCodeHandler)
 
 	log.Println("Listening on", *listen)
 	err := http.ListenAndServe(*listen, nil)
@@ -0,0 +1,109 @@
+package einvoice
+
+import (
+	"encoding/xml"
+	"errors"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+const (
+	ciiNamespace  = "urn:un:unece:uncefact:data:standard:CrossIndustryInvoice:100"
+	ciiDateLayout = "20060102" // Format 102
+	ciiCreditNote = "381"      // UNTDID 1001 document type
+)
+
+type ciiParty struct {
+	Name string
+}
+
+type ciiInvoice struct {
+	XMLName           xml.Name
+	ExchangedDocument struct {
+		ID       string
+		TypeCode string
+	}
+	SupplyChainTradeTransaction struct {
+		ApplicableHeaderTradeAgreement struct {
+			SellerTradeParty ciiParty
+		}
+		ApplicableHeaderTradeSettlement struct {
+			PaymentReference                     string
+			InvoiceCurrencyCode                  string
+			PayeeTradeParty                      *ciiParty
+			SpecifiedTradeSettlementPaymentMeans []struct {
+				PayeePartyCreditorFinancialAccount *struct {
+					IBANID        string
+					ProprietaryID string
+				}
+				PayeeSpecifiedCreditorFinancialInstitution struct {
+					BICID string
+				}
+			}
+			SpecifiedTradePaymentTerms []struct {
+				DueDateDateTime struct {
+					DateTimeString string
+				}
+			}
+			SpecifiedTradeSettlementHeaderMonetarySummation struct {
+				DuePayableAmount string
+			}
+		}
+	}
+}
+
+// FromCII creates an HCT code from an UN/CEFACT CII (EN 16931) invoice
+// The mapping is the same as the UBL one: the first payment means with a creditor account (IBAN or proprietary ID),
+// the payee or the seller name, the due payable amount in the invoice currency and the payment reference as the message.
+// Credit notes (type code 381) are rejected.
+func FromCII(b []byte, bic string) (*qr.Code, []qr.Loss, error) {
+	var doc ciiInvoice
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	if doc.XMLName.Space != ciiNamespace || doc.XMLName.Local != "CrossIndustryInvoice" {
+		return nil, nil, errors.New("not a CII invoice")
+	}
+
+	if doc.ExchangedDocument.TypeCode == ciiCreditNote {
+		return nil, nil, errCreditNote
+	}
+
+	transaction := doc.SupplyChainTradeTransaction
+	settlement := transaction.ApplicableHeaderTradeSettlement
+	p := payment{
+		invoiceID: doc.ExchangedDocument.ID,
+		name:      transaction.ApplicableHeaderTradeAgreement.SellerTradeParty.Name,
+		amount:    settlement.SpecifiedTradeSettlementHeaderMonetarySummation.DuePayableAmount,
+		currency:  settlement.InvoiceCurrencyCode,
+		dueLayout: ciiDateLayout,
+		reference: settlement.PaymentReference,
+	}
+	if settlement.PayeeTradeParty != nil && settlement.PayeeTradeParty.Name != "" {
+		p.name = settlement.PayeeTradeParty.Name
+	}
+
+	for _, terms := range settlement.SpecifiedTradePaymentTerms {
+		if d := terms.DueDateDateTime.DateTimeString; d != "" {
+			p.due = d
+			break
+		}
+	}
+
+	for _, m := range settlement.SpecifiedTradeSettlementPaymentMeans {
+		a := m.PayeePartyCreditorFinancialAccount
+		if a == nil {
+			continue
+		}
+
+		id := a.IBANID
+		if id == "" {
+			id = a.ProprietaryID
+		}
+		if id != "" {
+			p.accounts = append(p.accounts, account{id: id, bic: m.PayeeSpecifiedCreditorFinancialInstitution.BICID})
+		}
+	}
+	return p.code(bic)
+}
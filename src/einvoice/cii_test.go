@@ -0,0 +1,57 @@
+package einvoice
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+func TestFromCII(t *testing.T) {
+	c, losses, err := FromCII([]byte(readTestFile(t, "testdata/cii.xml")), "GIBAHUHB")
+	assert.NoError(t, err)
+	assert.Empty(t, losses)
+
+	assert.Equal(t, "GIBAHUHBXXX", c.BIC)
+	assert.Equal(t, "Teszt Faktor Zrt.", c.Name)
+	assert.Equal(t, "HU93116000060000000012345676", c.IBAN)
+	assert.Equal(t, "INV-2024-002", c.Get("invoiceID"))
+	assert.Equal(t, "INV-2024-002", c.Get("message"))
+	assert.Equal(t, "HUF12700", c.Get("amount"))
+	assert.Equal(t, time.Date(2099, 1, 31, 23, 59, 59, 0, time.Local), c.Expiry())
+	assert.NoError(t, c.Validate())
+}
+
+func TestFromCIILosses(t *testing.T) {
+	content := readTestFile(t, "testdata/cii.xml")
+	content = strings.Replace(content, "<ram:Name>Teszt Faktor Zrt.</ram:Name>", "", 1)
+	content = strings.Replace(content, "20990131", "", 1)
+	content = strings.Replace(content, "<ram:DuePayableAmount>12700.00", "<ram:DuePayableAmount>0", 1)
+
+	c, losses, err := FromCII([]byte(content), "GIBAHUHB")
+	assert.NoError(t, err)
+	assert.Equal(t, "Teszt Kft.", c.Name)
+	assert.Equal(t, []qr.Loss{
+		{Field: "amount", Value: "0", Reason: "amount is not payable: 0"},
+		{Field: "dueDate", Reason: "missing, the validity should be set"},
+	}, losses)
+}
+
+func TestFromCIIErrors(t *testing.T) {
+	content := readTestFile(t, "testdata/cii.xml")
+
+	_, _, err := FromCII([]byte(strings.Replace(content, "CrossIndustryInvoice:100", "CrossIndustryInvoice:99", 1)), "")
+	assert.EqualError(t, err, "not a CII invoice")
+
+	_, _, err = FromCII([]byte(strings.Replace(content, "<ram:TypeCode>380</ram:TypeCode>", "<ram:TypeCode>381</ram:TypeCode>", 1)), "")
+	assert.EqualError(t, err, "credit note, the supplier pays the buyer")
+
+	_, _, err = FromCII([]byte(strings.Replace(content, "ProprietaryID", "Other", 2)), "")
+	assert.EqualError(t, err, "missing payee account")
+
+	_, _, err = FromCII([]byte(content), "")
+	assert.Error(t, err)
+}
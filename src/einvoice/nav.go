@@ -0,0 +1,98 @@
+// Package einvoice creates MNB payment codes from electronic invoices
+package einvoice
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+const (
+	navNamespace = "http://schemas.nav.gov.hu/OSA/3.0/data"
+	dateLayout   = "2006-01-02"
+)
+
+type navInvoiceData struct {
+	XMLName       xml.Name
+	InvoiceNumber string `xml:"invoiceNumber"`
+	InvoiceMain   struct {
+		Invoice *struct {
+			InvoiceHead struct {
+				SupplierInfo struct {
+					SupplierName              string `xml:"supplierName"`
+					SupplierBankAccountNumber string `xml:"supplierBankAccountNumber"`
+				} `xml:"supplierInfo"`
+				InvoiceDetail struct {
+					PaymentDate string `xml:"paymentDate"`
+				} `xml:"invoiceDetail"`
+			} `xml:"invoiceHead"`
+			InvoiceSummary struct {
+				SummaryGrossData struct {
+					InvoiceGrossAmountHUF string `xml:"invoiceGrossAmountHUF"`
+				} `xml:"summaryGrossData"`
+			} `xml:"invoiceSummary"`
+		} `xml:"invoice"`
+	} `xml:"invoiceMain"`
+}
+
+// FromNAV creates an HCT code from a NAV Online Számla 3.0 invoice (InvoiceData) XML
+// The supplier name, bank account, invoice number, gross HUF total and payment date are used, the code is valid until
+// the end of the payment date. The BIC is not part of the invoice, it should be given by the caller.
+// The values which could not be mapped are returned as losses like for the UBL and CII invoices.
+func FromNAV(b []byte, bic string) (*qr.Code, []qr.Loss, error) {
+	var doc navInvoiceData
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	if doc.XMLName.Space != navNamespace || doc.XMLName.Local != "InvoiceData" {
+		return nil, nil, errors.New("not a NAV 3.0 invoice data")
+	}
+
+	invoice := doc.InvoiceMain.Invoice
+	if invoice == nil {
+		return nil, nil, errors.New("batch invoices are not supported")
+	}
+
+	supplier := invoice.InvoiceHead.SupplierInfo
+	if supplier.SupplierBankAccountNumber == "" {
+		return nil, nil, errors.New("missing supplier bank account number")
+	}
+
+	p := payment{
+		invoiceID: doc.InvoiceNumber,
+		name:      supplier.SupplierName,
+		accounts:  []account{{id: supplier.SupplierBankAccountNumber}},
+		amount:    invoice.InvoiceSummary.SummaryGrossData.InvoiceGrossAmountHUF,
+		due:       invoice.InvoiceHead.InvoiceDetail.PaymentDate,
+		dueLayout: dateLayout,
+	}
+	return p.code(bic)
+}
+
+// accountIBAN accepts an IBAN or a Hungarian account number
+func accountIBAN(account string) (string, error) {
+	account = strings.ReplaceAll(strings.TrimSpace(account), " ", "")
+	if len(account) > 2 && account[0] >= 'A' && account[0] <= 'Z' {
+		return account, qr.ValidateIBAN(account)
+	}
+	return qr.HungarianIBAN(account)
+}
+
+// wholeForints parses a positive decimal amount and rounds it to whole forints, rounded is set if it had a fraction
+func wholeForints(s string) (total int, rounded bool, err error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid amount: %s", s)
+	}
+
+	if v <= 0 {
+		return 0, false, fmt.Errorf("amount is not payable: %s", s)
+	}
+	return int(math.Round(v)), v != math.Trunc(v), nil
+}
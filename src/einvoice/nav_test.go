@@ -0,0 +1,82 @@
+package einvoice
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+func readTestFile(t *testing.T, file string) string {
+	b, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestFromNAV(t *testing.T) {
+	c, losses, err := FromNAV([]byte(readTestFile(t, "testdata/nav.xml")), "OTPVHUHB")
+	assert.NoError(t, err)
+	assert.Equal(t, []qr.Loss{{Field: "amount", Value: "12700.50", Reason: "rounded to whole forints"}}, losses)
+
+	assert.Equal(t, "Teszt Kft.", c.Name)
+	assert.Equal(t, "HU42117730161111101800000000", c.IBAN)
+	assert.Equal(t, "INV-2024-001", c.Get("invoiceID"))
+	assert.Equal(t, "HUF12701", c.Get("amount"))
+	assert.Equal(t, time.Date(2099, 1, 31, 23, 59, 59, 0, time.Local), c.Expiry())
+	assert.NoError(t, c.Validate())
+}
+
+func TestFromNAVIBAN(t *testing.T) {
+	content := strings.Replace(readTestFile(t, "testdata/nav.xml"), "11773016-11111018-00000000", "HU93 1160 0006 0000 0000 1234 5676", 1)
+	content = strings.Replace(content, "<paymentDate>2099-01-31</paymentDate>", "", 1)
+
+	c, losses, err := FromNAV([]byte(content), "GIBAHUHB")
+	assert.NoError(t, err)
+	assert.Equal(t, "HU93116000060000000012345676", c.IBAN)
+	assert.True(t, c.Expiry().IsZero())
+	assert.Contains(t, losses, qr.Loss{Field: "dueDate", Reason: "missing, the validity should be set"})
+}
+
+func TestFromNAVLosses(t *testing.T) {
+	content := readTestFile(t, "testdata/nav.xml")
+	for _, tc := range []struct {
+		old, new string
+		loss     qr.Loss
+	}{
+		{"<invoiceGrossAmountHUF>12700.50", "<invoiceGrossAmountHUF>-100", qr.Loss{Field: "amount", Value: "-100", Reason: "amount is not payable: -100"}},
+		{"<invoiceGrossAmountHUF>12700.50", "<invoiceGrossAmountHUF>x", qr.Loss{Field: "amount", Value: "x", Reason: "invalid amount: x"}},
+		{"2099-01-31", "2020-01-31", qr.Loss{Field: "dueDate", Value: "2020-01-31", Reason: "due date has passed, the validity should be set"}},
+		{"2099-01-31", "31/01/2099", qr.Loss{Field: "dueDate", Value: "31/01/2099", Reason: "invalid date"}},
+		{"Teszt Kft.", strings.Repeat("Teszt ", 12), qr.Loss{Field: "name", Value: strings.TrimSpace(strings.Repeat("Teszt ", 12)), Reason: "cut to 70 bytes"}},
+	} {
+		c, losses, err := FromNAV([]byte(strings.Replace(content, tc.old, tc.new, 1)), "OTPVHUHB")
+		assert.NoError(t, err, tc.old)
+		assert.NotNil(t, c)
+		assert.Contains(t, losses, tc.loss, tc.old)
+	}
+}
+
+func TestFromNAVErrors(t *testing.T) {
+	content := readTestFile(t, "testdata/nav.xml")
+	for _, tc := range []struct {
+		old, new string
+		err      string
+	}{
+		{"OSA/3.0/data", "OSA/2.0/data", "not a NAV 3.0 invoice data"},
+		{"<invoice>", "<batchInvoice>", "batch invoices are not supported"},
+		{"11773016-11111018-00000000", "", "missing supplier bank account number"},
+		{"11773016-11111018-00000000", "11773016-11111018-00000001", "invalid account number checksum"},
+	} {
+		modified := strings.Replace(content, tc.old, tc.new, 1)
+		if tc.old == "<invoice>" {
+			modified = strings.Replace(modified, "</invoice>", "</batchInvoice>", 1)
+		}
+
+		_, _, err := FromNAV([]byte(modified), "OTPVHUHB")
+		assert.EqualError(t, err, tc.err, tc.old)
+	}
+}
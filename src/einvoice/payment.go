@@ -0,0 +1,99 @@
+package einvoice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// errCreditNote is returned for the credit notes, those are paid by the supplier to the buyer
+var errCreditNote = errors.New("credit note, the supplier pays the buyer")
+
+// account of the payee from the payment means of an e-invoice
+type account struct {
+	id  string // IBAN or Hungarian account number
+	bic string
+}
+
+// payment is the payment information common in the UBL and CII invoices
+type payment struct {
+	invoiceID string
+	name      string
+	accounts  []account
+	amount    string
+	currency  string
+	due       string
+	dueLayout string
+	reference string
+}
+
+// code creates an HCT code, the not mapped values are returned as losses
+// The first account is used, the bic is the fallback if the invoice does not contain it.
+func (p payment) code(bic string) (*qr.Code, []qr.Loss, error) {
+	if len(p.accounts) == 0 {
+		return nil, nil, errors.New("missing payee account")
+	}
+
+	var losses []qr.Loss
+	for _, a := range p.accounts[1:] {
+		losses = append(losses, qr.Loss{Field: "account", Value: a.id, Reason: "only the first account is used"})
+	}
+
+	iban, err := accountIBAN(p.accounts[0].id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if p.accounts[0].bic != "" {
+		bic = p.accounts[0].bic
+	}
+
+	name := strings.TrimSpace(p.name)
+	if len(name) > 70 {
+		losses = append(losses, qr.Loss{Field: "name", Value: name, Reason: "cut to 70 bytes"})
+		name = qr.Truncate(name, 70)
+	}
+
+	c, err := qr.NewPaymentSend(bic, name, iban)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.InvoiceID(p.invoiceID); err != nil {
+		losses = append(losses, qr.Loss{Field: "invoiceID", Value: p.invoiceID, Reason: err.Error()})
+	}
+
+	if p.reference != "" {
+		message, _ := qr.LookupField("message")
+		msg := p.reference
+		if len(msg) > message.MaxLen {
+			losses = append(losses, qr.Loss{Field: "reference", Value: msg, Reason: fmt.Sprintf("cut to %d bytes", message.MaxLen)})
+			msg = qr.Truncate(msg, message.MaxLen)
+		}
+		if err := c.Message(msg); err != nil {
+			losses = append(losses, qr.Loss{Field: "reference", Value: p.reference, Reason: err.Error()})
+		}
+	}
+
+	if p.currency != "" && p.currency != "HUF" {
+		losses = append(losses, qr.Loss{Field: "amount", Value: p.currency + p.amount, Reason: "MNB supports only HUF amounts"})
+	} else if total, rounded, err := wholeForints(p.amount); err != nil {
+		losses = append(losses, qr.Loss{Field: "amount", Value: p.amount, Reason: err.Error()})
+	} else if err := c.HUFAmount(total); err != nil {
+		losses = append(losses, qr.Loss{Field: "amount", Value: p.amount, Reason: err.Error()})
+	} else if rounded {
+		losses = append(losses, qr.Loss{Field: "amount", Value: p.amount, Reason: "rounded to whole forints"})
+	}
+
+	if p.due == "" {
+		losses = append(losses, qr.Loss{Field: "dueDate", Reason: "missing, the validity should be set"})
+	} else if t, err := time.ParseInLocation(p.dueLayout, strings.TrimSpace(p.due), time.Local); err != nil {
+		losses = append(losses, qr.Loss{Field: "dueDate", Value: p.due, Reason: "invalid date"})
+	} else if err := c.ValidUntil(t.Add(24*time.Hour - time.Second)); err != nil {
+		losses = append(losses, qr.Loss{Field: "dueDate", Value: p.due, Reason: "due date has passed, the validity should be set"})
+	}
+	return c, losses, nil
+}
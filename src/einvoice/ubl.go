@@ -0,0 +1,116 @@
+package einvoice
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+const (
+	ublInvoiceNamespace    = "urn:oasis:names:specification:ubl:schema:xsd:Invoice-2"
+	ublCreditNoteNamespace = "urn:oasis:names:specification:ubl:schema:xsd:CreditNote-2"
+)
+
+type ublParty struct {
+	PartyName []struct {
+		Name string
+	}
+	PartyLegalEntity []struct {
+		RegistrationName string
+	}
+}
+
+// name of the party, the legal name is preferred
+func (p *ublParty) name() string {
+	if p == nil {
+		return ""
+	}
+	for _, e := range p.PartyLegalEntity {
+		if e.RegistrationName != "" {
+			return e.RegistrationName
+		}
+	}
+	for _, n := range p.PartyName {
+		if n.Name != "" {
+			return n.Name
+		}
+	}
+	return ""
+}
+
+type ublInvoice struct {
+	XMLName                 xml.Name
+	ID                      string
+	DueDate                 string
+	AccountingSupplierParty struct {
+		Party *ublParty
+	}
+	PayeeParty   *ublParty
+	PaymentMeans []struct {
+		PaymentDueDate        string
+		PaymentID             []string
+		PayeeFinancialAccount *struct {
+			ID                         string
+			FinancialInstitutionBranch struct {
+				ID string
+			}
+		}
+	}
+	LegalMonetaryTotal struct {
+		PayableAmount struct {
+			Value    string `xml:",chardata"`
+			Currency string `xml:"currencyID,attr"`
+		}
+	}
+}
+
+// FromUBL creates an HCT code from an UBL 2.1 (EN 16931) invoice
+// The payee account and BIC come from the first payment means with a financial account, the bic is used when it's missing.
+// The payee is the payee party or the supplier, the payable amount should be in HUF, the payment ID becomes the message.
+// The values which could not be mapped are returned as losses, the validity should be set if the due date is lost.
+// Credit notes are rejected, those are paid by the supplier.
+func FromUBL(b []byte, bic string) (*qr.Code, []qr.Loss, error) {
+	var doc ublInvoice
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	switch doc.XMLName.Space {
+	case ublInvoiceNamespace:
+	case ublCreditNoteNamespace:
+		return nil, nil, errCreditNote
+	default:
+		return nil, nil, errors.New("not an UBL invoice")
+	}
+
+	p := payment{
+		invoiceID: doc.ID,
+		name:      doc.PayeeParty.name(),
+		amount:    doc.LegalMonetaryTotal.PayableAmount.Value,
+		currency:  doc.LegalMonetaryTotal.PayableAmount.Currency,
+		due:       doc.DueDate,
+		dueLayout: dateLayout,
+	}
+	if p.name == "" {
+		p.name = doc.AccountingSupplierParty.Party.name()
+	}
+
+	for _, m := range doc.PaymentMeans {
+		if m.PayeeFinancialAccount == nil || m.PayeeFinancialAccount.ID == "" {
+			continue
+		}
+
+		p.accounts = append(p.accounts, account{id: m.PayeeFinancialAccount.ID, bic: m.PayeeFinancialAccount.FinancialInstitutionBranch.ID})
+		if len(p.accounts) > 1 {
+			continue
+		}
+
+		if p.due == "" {
+			p.due = m.PaymentDueDate // UBL 2.0
+		}
+		p.reference = strings.Join(m.PaymentID, " ")
+	}
+	return p.code(bic)
+}
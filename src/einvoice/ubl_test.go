@@ -0,0 +1,80 @@
+package einvoice
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+func TestFromUBL(t *testing.T) {
+	c, losses, err := FromUBL([]byte(readTestFile(t, "testdata/ubl.xml")), "")
+	assert.NoError(t, err)
+	assert.Equal(t, []qr.Loss{{Field: "account", Value: "HU93116000060000000012345676", Reason: "only the first account is used"}}, losses)
+
+	assert.Equal(t, "OTPVHUHBXXX", c.BIC)
+	assert.Equal(t, "Teszt Kft.", c.Name)
+	assert.Equal(t, "HU42117730161111101800000000", c.IBAN)
+	assert.Equal(t, "INV-2024-001", c.Get("invoiceID"))
+	assert.Equal(t, "INV-2024-001", c.Get("message"))
+	assert.Equal(t, "HUF12000", c.Get("amount"))
+	assert.Equal(t, time.Date(2099, 1, 31, 23, 59, 59, 0, time.Local), c.Expiry())
+	assert.NoError(t, c.Validate())
+}
+
+func TestFromUBLLosses(t *testing.T) {
+	content := readTestFile(t, "testdata/ubl.xml")
+	content = strings.Replace(content, "<cbc:ID>OTPVHUHB</cbc:ID>", "", 1)
+	content = strings.ReplaceAll(content, `currencyID="HUF"`, `currencyID="EUR"`)
+	content = strings.Replace(content, "2099-01-31", "2020-01-31", 1)
+	content = strings.Replace(content, "<cbc:PaymentID>INV-2024-001</cbc:PaymentID>", "<cbc:PaymentID>"+strings.Repeat("x", 80)+"</cbc:PaymentID>", 1)
+
+	c, losses, err := FromUBL([]byte(content), "GIBAHUHB")
+	assert.NoError(t, err)
+	assert.Equal(t, "GIBAHUHBXXX", c.BIC)
+	assert.Equal(t, []qr.Loss{
+		{Field: "account", Value: "HU93116000060000000012345676", Reason: "only the first account is used"},
+		{Field: "reference", Value: strings.Repeat("x", 80), Reason: "cut to 70 bytes"},
+		{Field: "amount", Value: "EUR12000.00", Reason: "MNB supports only HUF amounts"},
+		{Field: "dueDate", Value: "2020-01-31", Reason: "due date has passed, the validity should be set"},
+	}, losses)
+	assert.Equal(t, "", c.Get("amount"))
+	assert.True(t, c.Expiry().IsZero())
+
+	// The multi-byte reference is cut at a character boundary
+	content = strings.Replace(readTestFile(t, "testdata/ubl.xml"), "<cbc:PaymentID>INV-2024-001</cbc:PaymentID>", "<cbc:PaymentID>"+strings.Repeat("ő", 40)+"</cbc:PaymentID>", 1)
+	c, losses, err = FromUBL([]byte(content), "")
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("ő", 35), c.Get("message"))
+	assert.Equal(t, []qr.Loss{
+		{Field: "account", Value: "HU93116000060000000012345676", Reason: "only the first account is used"},
+		{Field: "reference", Value: strings.Repeat("ő", 40), Reason: "cut to 70 bytes"},
+	}, losses)
+
+	// The fraction of a forint is rounded
+	content = strings.Replace(readTestFile(t, "testdata/ubl.xml"), ">12000.00</cbc:PayableAmount>", ">12000.49</cbc:PayableAmount>", 1)
+	c, losses, err = FromUBL([]byte(content), "")
+	assert.NoError(t, err)
+	assert.Equal(t, "HUF12000", c.Get("amount"))
+	assert.Contains(t, losses, qr.Loss{Field: "amount", Value: "12000.49", Reason: "rounded to whole forints"})
+}
+
+func TestFromUBLErrors(t *testing.T) {
+	content := readTestFile(t, "testdata/ubl.xml")
+
+	_, _, err := FromUBL([]byte(strings.Replace(content, "Invoice-2", "Order-2", 1)), "")
+	assert.EqualError(t, err, "not an UBL invoice")
+
+	creditNote := strings.Replace(content, "<Invoice xmlns=\"urn:oasis:names:specification:ubl:schema:xsd:Invoice-2\"", "<CreditNote xmlns=\"urn:oasis:names:specification:ubl:schema:xsd:CreditNote-2\"", 1)
+	_, _, err = FromUBL([]byte(strings.Replace(creditNote, "</Invoice>", "</CreditNote>", 1)), "")
+	assert.EqualError(t, err, "credit note, the supplier pays the buyer")
+
+	_, _, err = FromUBL([]byte(strings.ReplaceAll(content, "PayeeFinancialAccount", "Other")), "")
+	assert.EqualError(t, err, "missing payee account")
+
+	_, _, err = FromUBL([]byte(strings.Replace(content, "HU42117730161111101800000000", "HU00117730161111101800000000", 1)), "")
+	assert.Error(t, err)
+}
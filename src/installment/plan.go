@@ -0,0 +1,142 @@
+// Package installment splits a total into instalments and creates a payment code for each of them
+package installment
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// Plan of the instalments, the amounts are in whole HUF
+type Plan struct {
+	Total      int       // Split into Count equal instalments, the remainder of the rounding is added to the first one
+	Count      int       // Number of the equal instalments
+	Amounts    []int     // Custom instalment amounts instead of the equal split, their sum should be the Total if it's set
+	First      time.Time // Due date of the first instalment
+	Months     int       // Months between the due dates, 1 by default
+	CustomerID string    // Shared by every code
+	Reference  string    // Required invoiceID prefix, the instalments get a -01, -02, ... suffix
+	Message    string    // Optional message of every code, the "1/3" style instalment number is added
+}
+
+// Instalment is one payment of the plan
+type Instalment struct {
+	Number int       // Starting from 1
+	Amount int       // Whole HUF
+	From   time.Time // The day after the previous due date, zero for the first instalment
+	Due    time.Time // The code is valid until the end of the day
+	Code   *qr.Code
+}
+
+// amounts returns the instalment amounts of the plan
+func (p Plan) amounts() ([]int, error) {
+	if len(p.Amounts) > 0 {
+		sum := 0
+		for i, a := range p.Amounts {
+			if a <= 0 {
+				return nil, fmt.Errorf("instalment %d: amount should be positive", i+1)
+			}
+			sum += a
+		}
+
+		if p.Total != 0 && sum != p.Total {
+			return nil, fmt.Errorf("sum of the amounts (%d) is not the total (%d)", sum, p.Total)
+		}
+		return p.Amounts, nil
+	}
+
+	if p.Count <= 0 {
+		return nil, errors.New("count should be positive")
+	}
+
+	if p.Total < p.Count {
+		return nil, errors.New("total is less than one forint per instalment")
+	}
+
+	amounts := make([]int, p.Count)
+	for i := range amounts {
+		amounts[i] = p.Total / p.Count
+	}
+	amounts[0] += p.Total % p.Count
+	return amounts, nil
+}
+
+// Schedule creates the instalments with their codes from the payee code (the recipient and the other fixed fields)
+func Schedule(p Plan, payee qr.Code) ([]Instalment, error) {
+	amounts, err := p.amounts()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.First.IsZero() {
+		return nil, errors.New("first due date is required")
+	}
+
+	if p.Reference == "" {
+		return nil, errors.New("reference is required")
+	}
+
+	months := p.Months
+	if months <= 0 {
+		months = 1
+	}
+
+	instalments := make([]Instalment, 0, len(amounts))
+	var from time.Time
+	for i, amount := range amounts {
+		due := addMonths(p.First, i*months)
+		inst := Instalment{Number: i + 1, Amount: amount, From: from, Due: due}
+
+		inst.Code, err = instalmentCode(p, payee, inst, len(amounts))
+		if err != nil {
+			return nil, fmt.Errorf("instalment %d: %v", i+1, err)
+		}
+		instalments = append(instalments, inst)
+		from = due.AddDate(0, 0, 1)
+	}
+	return instalments, nil
+}
+
+func instalmentCode(p Plan, payee qr.Code, inst Instalment, count int) (*qr.Code, error) {
+	c := payee
+	if err := c.HUFAmount(inst.Amount); err != nil {
+		return nil, err
+	}
+
+	if p.CustomerID != "" {
+		if err := c.CustomerID(p.CustomerID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.InvoiceID(fmt.Sprintf("%s-%02d", p.Reference, inst.Number)); err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%d/%d", inst.Number, count)
+	if p.Message != "" {
+		message = p.Message + " " + message
+	}
+	if err := c.Message(message); err != nil {
+		return nil, err
+	}
+
+	y, m, d := inst.Due.Date()
+	if err := c.ValidUntil(time.Date(y, m, d, 23, 59, 59, 0, inst.Due.Location())); err != nil {
+		return nil, errors.New("due date has passed")
+	}
+	return &c, nil
+}
+
+// addMonths adds the months to the date, the day is limited to the last day of the month (Jan 31 + 1 month is Feb 28)
+func addMonths(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	if d > last {
+		d = last
+	}
+	return time.Date(first.Year(), first.Month(), d, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,81 @@
+package installment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+func testPayee(t *testing.T) qr.Code {
+	c, err := qr.NewPaymentSend("OTPVHUHB", "Shop Ltd", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	return *c
+}
+
+func TestSchedule(t *testing.T) {
+	first := time.Date(2099, 1, 31, 0, 0, 0, 0, time.Local)
+	instalments, err := Schedule(Plan{Total: 10000, Count: 3, First: first, CustomerID: "C42", Reference: "INV-7", Message: "Részlet"}, testPayee(t))
+	assert.NoError(t, err)
+	assert.Len(t, instalments, 3)
+
+	var amounts []int
+	var dues []string
+	for _, inst := range instalments {
+		amounts = append(amounts, inst.Amount)
+		dues = append(dues, inst.Due.Format(dateLayout))
+		assert.NoError(t, inst.Code.Validate())
+		assert.Equal(t, "C42", inst.Code.Get("customerID"))
+	}
+	assert.Equal(t, []int{3334, 3333, 3333}, amounts)
+	assert.Equal(t, []string{"2099-01-31", "2099-02-28", "2099-03-31"}, dues)
+
+	assert.True(t, instalments[0].From.IsZero())
+	assert.Equal(t, time.Date(2099, 2, 1, 0, 0, 0, 0, time.Local), instalments[1].From)
+
+	c := instalments[1].Code
+	assert.Equal(t, "HUF3333", c.Get("amount"))
+	assert.Equal(t, "INV-7-02", c.Get("invoiceID"))
+	assert.Equal(t, "Részlet 2/3", c.Get("message"))
+	assert.Equal(t, time.Date(2099, 2, 28, 23, 59, 59, 0, time.Local), c.Expiry())
+}
+
+func TestScheduleCustom(t *testing.T) {
+	first := time.Date(2099, 1, 15, 0, 0, 0, 0, time.Local)
+	instalments, err := Schedule(Plan{Amounts: []int{5000, 2500, 2500}, Total: 10000, First: first, Months: 3, Reference: "C-1"}, testPayee(t))
+	assert.NoError(t, err)
+	assert.Len(t, instalments, 3)
+	assert.Equal(t, 2500, instalments[2].Amount)
+	assert.Equal(t, time.Date(2099, 7, 15, 0, 0, 0, 0, time.Local), instalments[2].Due)
+	assert.Equal(t, "1/3", instalments[0].Code.Get("message"))
+	assert.Equal(t, "C-1-01", instalments[0].Code.Get("invoiceID"))
+	assert.Equal(t, "C-1-03", instalments[2].Code.Get("invoiceID"))
+}
+
+func TestScheduleErrors(t *testing.T) {
+	first := time.Date(2099, 1, 15, 0, 0, 0, 0, time.Local)
+	for _, tt := range []struct {
+		plan Plan
+		err  string
+	}{
+		{Plan{Total: 100, First: first}, "count should be positive"},
+		{Plan{Total: 2, Count: 3, First: first}, "total is less than one forint per instalment"},
+		{Plan{Amounts: []int{100, 0}, First: first}, "instalment 2: amount should be positive"},
+		{Plan{Amounts: []int{100, 50}, Total: 200, First: first}, "sum of the amounts (150) is not the total (200)"},
+		{Plan{Total: 100, Count: 1, Reference: "R"}, "first due date is required"},
+		{Plan{Total: 100, Count: 1, First: first}, "reference is required"},
+		{Plan{Total: 100, Count: 1, First: time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local), Reference: "R"}, "instalment 1: due date has passed"},
+		{Plan{Total: 100, Count: 1, First: first, Reference: "INV-123456789012345678901234567890"}, "instalment 1: invoiceID is too long"},
+	} {
+		_, err := Schedule(tt.plan, testPayee(t))
+		assert.EqualError(t, err, tt.err)
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), addMonths(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 1))
+	assert.Equal(t, time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), addMonths(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), 1))
+	assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), addMonths(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), 0))
+}
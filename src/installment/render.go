@@ -0,0 +1,242 @@
+package installment
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	pdfPageWidth    = 595 // A4 in points
+	pdfMargin       = 56
+	maxPDFImageSize = pdfPageWidth - 2*pdfMargin
+)
+
+// pdfEncoding is WinAnsi with the Hungarian double acute letters on its unused codes, the standard fonts have the glyphs
+const pdfEncoding = "<< /Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences [129 /Ohungarumlaut 141 /ohungarumlaut 143 /Uhungarumlaut 144 /uhungarumlaut] >>"
+
+var pdfDifferences = map[rune]byte{'Ő': 129, 'ő': 141, 'Ű': 143, 'ű': 144}
+
+// WriteZIP writes a PNG image and the text payload of every instalment code into a ZIP archive
+func WriteZIP(w io.Writer, instalments []Instalment, opts qr.RenderOptions) error {
+	zw := zip.NewWriter(w)
+	for _, inst := range instalments {
+		name := fmt.Sprintf("%02d-%s", inst.Number, inst.Due.Format(dateLayout))
+
+		f, err := zw.Create(name + ".png")
+		if err != nil {
+			return err
+		}
+		if err := inst.Code.WritePNG(f, opts); err != nil {
+			return fmt.Errorf("instalment %d: %v", inst.Number, err)
+		}
+
+		f, err = zw.Create(name + ".txt")
+		if err != nil {
+			return err
+		}
+		if _, err := inst.Code.WriteTo(f); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// WritePDF writes an A4 page for every instalment with the code image and the payment details
+// The image size of the options is used in points, 256 by default and at most 483 to fit the page. The texts could
+// contain the Latin-1 and the Hungarian letters.
+func WritePDF(w io.Writer, instalments []Instalment, opts qr.RenderOptions) error {
+	if len(instalments) == 0 {
+		return errors.New("no instalments")
+	}
+
+	if opts.Size <= 0 {
+		opts.Size = 256
+	}
+	if opts.Size > maxPDFImageSize {
+		return fmt.Errorf("image size %d does not fit the page, at most %d is allowed", opts.Size, maxPDFImageSize)
+	}
+
+	pdf := &pdfWriter{}
+	encoding := pdf.add(pdfEncoding)
+	font := pdf.add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding %s >>", encoding))
+	pages := pdf.reserve()
+
+	var kids []string
+	for _, inst := range instalments {
+		var buf bytes.Buffer
+		if err := inst.Code.WritePNG(&buf, opts); err != nil {
+			return fmt.Errorf("instalment %d: %v", inst.Number, err)
+		}
+
+		img, err := png.Decode(&buf)
+		if err != nil {
+			return err
+		}
+
+		imageRef, err := pdf.addImage(img)
+		if err != nil {
+			return err
+		}
+
+		content, err := pageContent(inst, len(instalments), img.Bounds().Dx())
+		if err != nil {
+			return fmt.Errorf("instalment %d: %v", inst.Number, err)
+		}
+		contentRef := pdf.addStream("", []byte(content))
+		kids = append(kids, pdf.add(fmt.Sprintf("<< /Type /Page /Parent %s /MediaBox [0 0 %d 842] /Resources << /Font << /F1 %s >> /XObject << /Im1 %s >> >> /Contents %s >>",
+			pages, pdfPageWidth, font, imageRef, contentRef)))
+	}
+
+	pdf.set(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	catalog := pdf.add(fmt.Sprintf("<< /Type /Catalog /Pages %s >>", pages))
+	return pdf.writeTo(w, catalog)
+}
+
+// pageContent draws the details from the top and the image below them
+func pageContent(inst Instalment, count int, size int) (string, error) {
+	c := inst.Code
+	lines := []string{
+		fmt.Sprintf("Instalment %d/%d", inst.Number, count),
+		fmt.Sprintf("Amount: %d HUF", inst.Amount),
+		"Due date: " + inst.Due.Format(dateLayout),
+		"Payee: " + c.Name,
+		"IBAN: " + c.IBAN,
+	}
+	if id := c.Get("invoiceID"); id != "" {
+		lines = append(lines, "Reference: "+id)
+	}
+	if id := c.Get("customerID"); id != "" {
+		lines = append(lines, "Customer ID: "+id)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "BT /F1 18 Tf %d 780 Td 24 TL\n", pdfMargin)
+	for i, line := range lines {
+		if i == 1 {
+			sb.WriteString("/F1 12 Tf 18 TL\n")
+		}
+
+		text, err := pdfText(line)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "(%s) Tj T*\n", text)
+	}
+	sb.WriteString("ET\n")
+
+	top := 780 - 24 - 18*len(lines) - 24
+	fmt.Fprintf(&sb, "q %d 0 0 %d %d %d cm /Im1 Do Q\n", size, size, (pdfPageWidth-size)/2, top-size)
+	return sb.String(), nil
+}
+
+// pdfText encodes the text to the pdfEncoding of the font, an error is returned for the characters out of it
+func pdfText(s string) (string, error) {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			sb.WriteByte(byte(r))
+		case pdfDifferences[r] != 0:
+			sb.WriteByte(pdfDifferences[r])
+		default:
+			return "", fmt.Errorf("character %q could not be written to the PDF", r)
+		}
+	}
+	return sb.String(), nil
+}
+
+// pdfWriter collects the objects of a PDF document, the references are "n 0 R" strings
+type pdfWriter struct {
+	objects [][]byte
+}
+
+func (p *pdfWriter) reserve() string {
+	p.objects = append(p.objects, nil)
+	return fmt.Sprintf("%d 0 R", len(p.objects))
+}
+
+func (p *pdfWriter) set(ref string, object string) {
+	var n int
+	_, _ = fmt.Sscanf(ref, "%d", &n)
+	p.objects[n-1] = []byte(object)
+}
+
+func (p *pdfWriter) add(object string) string {
+	ref := p.reserve()
+	p.set(ref, object)
+	return ref
+}
+
+func (p *pdfWriter) addStream(dict string, data []byte) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<< %s/Length %d >>\nstream\n", dict, len(data))
+	buf.Write(data)
+	buf.WriteString("\nendstream")
+
+	ref := p.reserve()
+	p.objects[len(p.objects)-1] = buf.Bytes()
+	return ref
+}
+
+// addImage adds the image as an 8 bit gray, deflate compressed image object
+func (p *pdfWriter) addImage(img image.Image) (string, error) {
+	b := img.Bounds()
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	row := make([]byte, b.Dx())
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			row[x-b.Min.X] = byte((r*299 + g*587 + bl*114) / 1000 >> 8)
+		}
+		if _, err := zw.Write(row); err != nil {
+			return "", err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+
+	dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceGray /BitsPerComponent 8 /Filter /FlateDecode ", b.Dx(), b.Dy())
+	return p.addStream(dict, buf.Bytes()), nil
+}
+
+// writeTo writes the objects with the cross-reference table
+func (p *pdfWriter) writeTo(w io.Writer, root string) error {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	offsets := make([]int, len(p.objects))
+	for i, object := range p.objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", i+1)
+		buf.Write(object)
+		buf.WriteString("\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(p.objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %s >>\nstartxref\n%d\n%%%%EOF\n", len(p.objects)+1, root, xref)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
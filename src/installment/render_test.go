@@ -0,0 +1,89 @@
+package installment
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+func testInstalments(t *testing.T) []Instalment {
+	instalments, err := Schedule(Plan{Total: 10000, Count: 2, First: time.Date(2099, 1, 31, 0, 0, 0, 0, time.Local), Reference: "INV-7"}, testPayee(t))
+	assert.NoError(t, err)
+	return instalments
+}
+
+func TestWriteZIP(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteZIP(&buf, testInstalments(t), qr.RenderOptions{Size: 256, Verify: true}))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"01-2099-01-31.png", "01-2099-01-31.txt", "02-2099-02-28.png", "02-2099-02-28.txt"}, names)
+
+	r, err := zr.File[3].Open()
+	assert.NoError(t, err)
+	payload, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+
+	c, err := qr.Parse(string(payload))
+	assert.NoError(t, err)
+	assert.Equal(t, "INV-7-02", c.Get("invoiceID"))
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WritePDF(&buf, testInstalments(t), qr.RenderOptions{}))
+	pdf := buf.Bytes()
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "/Type /Pages /Kids [6 0 R 9 0 R] /Count 2")
+	assert.Contains(t, string(pdf), "(Instalment 2/2) Tj")
+	assert.Contains(t, string(pdf), "(Reference: INV-7-02) Tj")
+
+	// The cross-reference table points to the objects
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	assert.NotNil(t, startxref)
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	assert.True(t, bytes.HasPrefix(pdf[xref:], []byte("xref\n0 11\n")))
+
+	for i, m := range regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(pdf, -1) {
+		offset, _ := strconv.Atoi(string(m[1]))
+		assert.True(t, bytes.HasPrefix(pdf[offset:], []byte(strconv.Itoa(i+1)+" 0 obj\n")), i)
+	}
+}
+
+func TestWritePDFErrors(t *testing.T) {
+	var buf bytes.Buffer
+	assert.EqualError(t, WritePDF(&buf, testInstalments(t), qr.RenderOptions{Size: 5, Verify: true}),
+		"instalment 1: image size 5 is too small, the code has 49 modules, at least 98 pixels are needed")
+	assert.EqualError(t, WritePDF(&buf, testInstalments(t), qr.RenderOptions{Size: 600}), "image size 600 does not fit the page, at most 483 is allowed")
+	assert.EqualError(t, WritePDF(&buf, nil, qr.RenderOptions{}), "no instalments")
+
+	instalments := testInstalments(t)
+	instalments[1].Code.Name = "Shop €"
+	assert.EqualError(t, WritePDF(&buf, instalments, qr.RenderOptions{}), "instalment 2: character '€' could not be written to the PDF")
+}
+
+func TestPDFText(t *testing.T) {
+	text, err := pdfText("Számla (fűtés)")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sz\xe1mla \\(f\x90t\xe9s\\)", text)
+
+	text, err = pdfText("ŐŰőű")
+	assert.NoError(t, err)
+	assert.Equal(t, "\x81\x8f\x8d\x90", text)
+}
@@ -0,0 +1,159 @@
+// Package invoice computes the payable amount of an invoice and creates the payment code for it
+package invoice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// Item is a line of the invoice, the amounts are in hundredths of HUF
+type Item struct {
+	Description string
+	Quantity    float64
+	UnitPrice   int // Net unit price in hundredths
+	VATRate     int // Percent, e.g. 27, 18, 5 or 0
+	Discount    int // Percent discount of the line
+}
+
+// Net amount of the line in hundredths, rounded to the nearest hundredth
+func (i Item) Net() int {
+	return int(math.Round(i.Quantity * float64(i.UnitPrice) * float64(100-i.Discount) / 100))
+}
+
+// Invoice to be paid by an HCT code
+type Invoice struct {
+	Number   string
+	Due      time.Time // The code is valid until the end of the day
+	Items    []Item
+	Discount int    // Discount of the net total in hundredths, spread over the VAT rates in proportion to their net
+	Message  string // Message of the code, "Számla <number>" by default
+}
+
+// RateSummary is the VAT summary of a rate
+type RateSummary struct {
+	Rate     int
+	Net      int // Base of the VAT, the net of the items minus the share of the discount
+	Discount int // Share of the invoice discount
+	VAT      int
+}
+
+// Summary of the invoice, the amounts are in hundredths of HUF except the payable amount
+type Summary struct {
+	Rates    []RateSummary // In ascending order of the rates
+	Net      int           // Net after the discount
+	Discount int
+	VAT      int
+	Gross    int // Net and VAT
+	Payable  int // Gross rounded to whole HUF
+	Rounding int // Payable minus gross in hundredths, the difference caused by the HUF rounding
+}
+
+// Summary calculates the totals, the VAT is calculated from the net sum of each rate after its share of the discount
+func (inv Invoice) Summary() (Summary, error) {
+	if len(inv.Items) == 0 {
+		return Summary{}, errors.New("invoice has no items")
+	}
+
+	if inv.Discount < 0 {
+		return Summary{}, errors.New("discount could not be negative")
+	}
+
+	nets := make(map[int]int)
+	for i, item := range inv.Items {
+		switch {
+		case item.Quantity <= 0:
+			return Summary{}, fmt.Errorf("item %d: quantity should be positive", i+1)
+		case item.UnitPrice < 0:
+			return Summary{}, fmt.Errorf("item %d: unit price could not be negative", i+1)
+		case item.VATRate < 0 || item.VATRate > 100:
+			return Summary{}, fmt.Errorf("item %d: invalid VAT rate", i+1)
+		case item.Discount < 0 || item.Discount > 100:
+			return Summary{}, fmt.Errorf("item %d: invalid discount", i+1)
+		}
+		nets[item.VATRate] += item.Net()
+	}
+
+	var s Summary
+	total, largest := 0, 0
+	for rate, net := range nets {
+		s.Rates = append(s.Rates, RateSummary{Rate: rate, Net: net})
+		total += net
+	}
+	sort.Slice(s.Rates, func(i, j int) bool { return s.Rates[i].Rate < s.Rates[j].Rate })
+
+	if inv.Discount > total {
+		return Summary{}, errors.New("discount is higher than the net total")
+	}
+
+	// The discount is split in proportion to the net of the rates, the rounding remainder goes to the largest one
+	remainder := inv.Discount
+	for i, r := range s.Rates {
+		if total > 0 {
+			s.Rates[i].Discount = inv.Discount * r.Net / total
+		}
+		remainder -= s.Rates[i].Discount
+		if r.Net > s.Rates[largest].Net {
+			largest = i
+		}
+	}
+	s.Rates[largest].Discount += remainder
+
+	for i, r := range s.Rates {
+		net := r.Net - r.Discount
+		vat := int(math.Round(float64(net) * float64(r.Rate) / 100))
+		s.Rates[i].Net, s.Rates[i].VAT = net, vat
+		s.Net += net
+		s.Discount += r.Discount
+		s.VAT += vat
+	}
+
+	s.Gross = s.Net + s.VAT
+	if s.Gross <= 0 {
+		return Summary{}, errors.New("nothing to pay")
+	}
+
+	s.Payable = (s.Gross + 50) / 100
+	s.Rounding = s.Payable*100 - s.Gross
+	return s, nil
+}
+
+// Code creates the payment code from the payee code (the recipient and the other fixed fields)
+// The amount, the invoiceID, the message and the validity are set from the invoice.
+func (inv Invoice) Code(payee qr.Code) (*qr.Code, Summary, error) {
+	s, err := inv.Summary()
+	if err != nil {
+		return nil, s, err
+	}
+
+	c := payee
+	if err := c.HUFAmount(s.Payable); err != nil {
+		return nil, s, err
+	}
+
+	if err := c.InvoiceID(inv.Number); err != nil {
+		return nil, s, err
+	}
+
+	message := inv.Message
+	if message == "" && inv.Number != "" {
+		message = "Számla " + inv.Number
+	}
+	if err := c.Message(message); err != nil {
+		return nil, s, err
+	}
+
+	if inv.Due.IsZero() {
+		return nil, s, errors.New("due date is required")
+	}
+
+	y, m, d := inv.Due.Date()
+	if err := c.ValidUntil(time.Date(y, m, d, 23, 59, 59, 0, inv.Due.Location())); err != nil {
+		return nil, s, errors.New("due date has passed")
+	}
+	return &c, s, nil
+}
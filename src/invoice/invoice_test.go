@@ -0,0 +1,128 @@
+package invoice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+func testInvoice() Invoice {
+	return Invoice{
+		Number: "INV-2024-001",
+		Due:    time.Date(2099, 1, 31, 0, 0, 0, 0, time.Local),
+		Items: []Item{
+			{Description: "Widget", Quantity: 3, UnitPrice: 33333, VATRate: 27},
+			{Description: "Book", Quantity: 1, UnitPrice: 10000, VATRate: 5, Discount: 10},
+		},
+	}
+}
+
+func TestSummary(t *testing.T) {
+	s, err := testInvoice().Summary()
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{
+		Rates: []RateSummary{
+			{Rate: 5, Net: 9000, VAT: 450},
+			{Rate: 27, Net: 99999, VAT: 27000},
+		},
+		Net:      108999,
+		VAT:      27450,
+		Gross:    136449,
+		Payable:  1364,
+		Rounding: -49,
+	}, s)
+
+	// The discount reduces the VAT bases in proportion to their net
+	inv := testInvoice()
+	inv.Discount = 1000
+	s, err = inv.Summary()
+	assert.NoError(t, err)
+	assert.Equal(t, Summary{
+		Rates: []RateSummary{
+			{Rate: 5, Net: 8918, Discount: 82, VAT: 446},
+			{Rate: 27, Net: 99081, Discount: 918, VAT: 26752},
+		},
+		Net:      107999,
+		Discount: 1000,
+		VAT:      27198,
+		Gross:    135197,
+		Payable:  1352,
+		Rounding: 3,
+	}, s)
+
+	inv.Discount = 108999
+	inv.Items[0].VATRate = 0
+	inv.Items[1].VATRate = 0
+	_, err = inv.Summary()
+	assert.EqualError(t, err, "nothing to pay")
+}
+
+func TestItemNet(t *testing.T) {
+	assert.Equal(t, 25050, Item{Quantity: 2.5, UnitPrice: 10020}.Net())
+	assert.Equal(t, 1667, Item{Quantity: 1, UnitPrice: 3333, Discount: 50}.Net())
+}
+
+func TestSummaryErrors(t *testing.T) {
+	for _, tt := range []struct {
+		modify func(inv *Invoice)
+		err    string
+	}{
+		{func(inv *Invoice) { inv.Items = nil }, "invoice has no items"},
+		{func(inv *Invoice) { inv.Discount = -1 }, "discount could not be negative"},
+		{func(inv *Invoice) { inv.Discount = 109000 }, "discount is higher than the net total"},
+		{func(inv *Invoice) { inv.Items[1].Quantity = 0 }, "item 2: quantity should be positive"},
+		{func(inv *Invoice) { inv.Items[0].UnitPrice = -1 }, "item 1: unit price could not be negative"},
+		{func(inv *Invoice) { inv.Items[0].VATRate = 101 }, "item 1: invalid VAT rate"},
+		{func(inv *Invoice) { inv.Items[0].Discount = -5 }, "item 1: invalid discount"},
+	} {
+		inv := testInvoice()
+		tt.modify(&inv)
+		_, err := inv.Summary()
+		assert.EqualError(t, err, tt.err)
+	}
+}
+
+func TestCode(t *testing.T) {
+	payee, err := qr.NewPaymentSend("OTPVHUHB", "Shop Ltd", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	assert.NoError(t, payee.Purpose("GDSV"))
+
+	c, s, err := testInvoice().Code(*payee)
+	assert.NoError(t, err)
+	assert.Equal(t, 1364, s.Payable)
+	assert.Equal(t, "HUF1364", c.Get("amount"))
+	assert.Equal(t, "INV-2024-001", c.Get("invoiceID"))
+	assert.Equal(t, "Számla INV-2024-001", c.Get("message"))
+	assert.Equal(t, "GDSV", c.Get("purpose"))
+	assert.Equal(t, time.Date(2099, 1, 31, 23, 59, 59, 0, time.Local), c.Expiry())
+	assert.NoError(t, c.Validate())
+
+	// The payee code is not modified
+	assert.Equal(t, "", payee.Get("invoiceID"))
+
+	inv := testInvoice()
+	inv.Message = "Order 77"
+	c, _, err = inv.Code(*payee)
+	assert.NoError(t, err)
+	assert.Equal(t, "Order 77", c.Get("message"))
+
+	inv.Due = time.Time{}
+	_, _, err = inv.Code(*payee)
+	assert.EqualError(t, err, "due date is required")
+
+	inv.Due = time.Date(2020, 1, 31, 0, 0, 0, 0, time.Local)
+	_, _, err = inv.Code(*payee)
+	assert.EqualError(t, err, "due date has passed")
+
+	inv = testInvoice()
+	inv.Number = "INV-123456789012345678901234567890123"
+	_, _, err = inv.Code(*payee)
+	assert.EqualError(t, err, "invoiceID is too long")
+
+	inv.Items = nil
+	_, _, err = inv.Code(*payee)
+	assert.EqualError(t, err, "invoice has no items")
+}
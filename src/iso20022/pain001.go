@@ -0,0 +1,284 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// Pain001Version of the credit transfer initiation message
+type Pain001Version string
+
+// Supported pain.001 versions
+const (
+	Pain001V03 Pain001Version = "pain.001.001.03"
+	Pain001V09 Pain001Version = "pain.001.001.09"
+)
+
+// LookupPain001Version accepts the full version name or only the last part (03 or 09), empty is the default version
+func LookupPain001Version(s string) (Pain001Version, error) {
+	switch s {
+	case "", "03", string(Pain001V03):
+		return Pain001V03, nil
+	case "09", string(Pain001V09):
+		return Pain001V09, nil
+	}
+	return "", fmt.Errorf("unsupported version: %s", s)
+}
+
+// Transfer is a single credit transfer of the batch from a scanned HCT code
+type Transfer struct {
+	Code       qr.Code
+	Execution  time.Time // Requested execution date, the batch execution date if empty
+	EndToEndID string    // The invoice ID of the code or NOTPROVIDED if empty
+}
+
+// Batch contains the payer side of the credit transfers
+type Batch struct {
+	MessageID string         // Required, unique identifier of the message (35 chars max)
+	Version   Pain001Version // Pain001V03 if empty
+	Created   time.Time      // Now if empty
+	Execution time.Time      // Requested execution date, the creation day if empty
+	Debtor    Party          // Required, the payer
+}
+
+type document001 struct {
+	XMLName    xml.Name `xml:"Document"`
+	Xmlns      string   `xml:"xmlns,attr"`
+	Initiation struct {
+		GrpHdr struct {
+			MsgID    string `xml:"MsgId"`
+			CreDtTm  string
+			NbOfTxs  int
+			CtrlSum  string
+			InitgPty name
+		}
+		PmtInf []paymentInformation
+	} `xml:"CstmrCdtTrfInitn"`
+}
+
+type paymentInformation struct {
+	PmtInfID    string `xml:"PmtInfId"`
+	PmtMtd      string
+	NbOfTxs     int
+	CtrlSum     string
+	ReqdExctnDt interface{} // Date in version 03, a date choice in 09
+	Dbtr        name
+	DbtrAcct    account
+	DbtrAgt     agent001
+	ChrgBr      string
+	CdtTrfTxInf []creditTransfer
+}
+
+type creditTransfer struct {
+	PmtID struct {
+		EndToEndID string `xml:"EndToEndId"`
+	} `xml:"PmtId"`
+	Amt struct {
+		InstdAmt instructedAmount
+	}
+	CdtrAgt  agent001
+	Cdtr     name
+	CdtrAcct account
+	Purp     *struct {
+		Cd string
+	} `xml:",omitempty"`
+	RmtInf *struct {
+		Ustrd string
+	} `xml:",omitempty"`
+}
+
+// agent001 has the BIC element of version 03 and the BICFI of 09
+type agent001 struct {
+	FinInstnID struct {
+		BIC   string `xml:",omitempty"`
+		BICFI string `xml:",omitempty"`
+	} `xml:"FinInstnId"`
+}
+
+type transferGroup struct {
+	execution string
+	transfers []creditTransfer
+	total     int
+}
+
+// Pain001 exports the HCT codes to a customer credit transfer initiation message
+// The transfers are grouped by the requested execution date, every group and the whole message have a control sum.
+// The remittance information is the invoice ID and the message of the code.
+func Pain001(transfers []Transfer, batch Batch) ([]byte, error) {
+	version := batch.Version
+	if version == "" {
+		version = Pain001V03
+	}
+	if version != Pain001V03 && version != Pain001V09 {
+		return nil, fmt.Errorf("unsupported version: %s", version)
+	}
+
+	if batch.MessageID == "" || len(batch.MessageID) > 35 {
+		return nil, errors.New("message id is required (35 chars max)")
+	}
+
+	if batch.Debtor.Name == "" || batch.Debtor.IBAN == "" || batch.Debtor.BIC == "" {
+		return nil, errors.New("debtor name, iban and bic are required")
+	}
+
+	if len(transfers) == 0 {
+		return nil, errors.New("no transfer in the batch")
+	}
+
+	created := batch.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	execution := batch.Execution
+	if execution.IsZero() {
+		execution = created
+	}
+
+	groups := make(map[string]*transferGroup)
+	total := 0
+	for i, t := range transfers {
+		tx, amount, err := newCreditTransfer(t, version)
+		if err != nil {
+			return nil, fmt.Errorf("transfer %d: %v", i+1, err)
+		}
+
+		date := execution.Format(dateLayout)
+		if !t.Execution.IsZero() {
+			date = t.Execution.Format(dateLayout)
+		}
+
+		g, ok := groups[date]
+		if !ok {
+			g = &transferGroup{execution: date}
+			groups[date] = g
+		}
+		g.transfers = append(g.transfers, tx)
+		g.total += amount
+		total += amount
+	}
+
+	dates := make([]string, 0, len(groups))
+	for date := range groups {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	doc := document001{Xmlns: "urn:iso:std:iso:20022:tech:xsd:" + string(version)}
+	hdr := &doc.Initiation.GrpHdr
+	hdr.MsgID = batch.MessageID
+	hdr.CreDtTm = created.Format(dateTimeLayout)
+	hdr.NbOfTxs = len(transfers)
+	hdr.CtrlSum = strconv.Itoa(total)
+	hdr.InitgPty.Nm = batch.Debtor.Name
+
+	for i, date := range dates {
+		g := groups[date]
+		suffix, prefix := fmt.Sprintf("-%d", i+1), batch.MessageID
+		if len(prefix)+len(suffix) > 35 {
+			prefix = prefix[:35-len(suffix)]
+		}
+
+		pmt := paymentInformation{
+			PmtInfID: prefix + suffix,
+			PmtMtd:   "TRF",
+			NbOfTxs:  len(g.transfers),
+			CtrlSum:  strconv.Itoa(g.total),
+			ChrgBr:   "SLEV",
+		}
+
+		if version == Pain001V03 {
+			pmt.ReqdExctnDt = date
+		} else {
+			pmt.ReqdExctnDt = struct{ Dt string }{date}
+		}
+		pmt.Dbtr.Nm = batch.Debtor.Name
+		pmt.DbtrAcct.ID.IBAN = batch.Debtor.IBAN
+		pmt.DbtrAgt = newAgent(batch.Debtor.BIC, version)
+		pmt.CdtTrfTxInf = g.transfers
+		doc.Initiation.PmtInf = append(doc.Initiation.PmtInf, pmt)
+	}
+
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// newCreditTransfer returns the transaction and its HUF amount
+func newCreditTransfer(t Transfer, version Pain001Version) (creditTransfer, int, error) {
+	var tx creditTransfer
+	c := t.Code
+	if c.Kind != qr.KindHCT {
+		return tx, 0, errors.New("only HCT codes could be exported")
+	}
+
+	if c.Get("amount") == "" {
+		return tx, 0, errors.New("amount is required")
+	}
+
+	if expiry := c.Expiry(); !expiry.IsZero() && time.Now().After(expiry) {
+		return tx, 0, errors.New("the code is expired")
+	}
+
+	amount, err := strconv.Atoi(c.Amount.Decimal())
+	if err != nil {
+		return tx, 0, err
+	}
+
+	endToEndID := t.EndToEndID
+	if endToEndID == "" {
+		endToEndID = c.Get("invoiceID")
+	}
+	if endToEndID == "" {
+		endToEndID = notProvided
+	}
+
+	tx.PmtID.EndToEndID = endToEndID
+	tx.Amt.InstdAmt = instructedAmount{Ccy: "HUF", Value: c.Amount.Decimal()}
+	tx.CdtrAgt = newAgent(c.BIC, version)
+	tx.Cdtr.Nm = c.Name
+	tx.CdtrAcct.ID.IBAN = c.IBAN
+	if purpose := c.Get("purpose"); purpose != "" {
+		tx.Purp = &struct{ Cd string }{purpose}
+	}
+	if info := remittance(c); info != "" {
+		tx.RmtInf = &struct{ Ustrd string }{info}
+	}
+	return tx, amount, nil
+}
+
+// remittance joins the invoice ID and the message, cut to the 140 characters of the unstructured remittance
+func remittance(c qr.Code) string {
+	var parts []string
+	for _, v := range []string{c.Get("invoiceID"), c.Get("message")} {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+
+	info := strings.Join(parts, " ")
+	if utf8.RuneCountInString(info) > 140 {
+		info = string([]rune(info)[:140])
+	}
+	return info
+}
+
+func newAgent(bic string, version Pain001Version) agent001 {
+	var a agent001
+	if version == Pain001V03 {
+		a.FinInstnID.BIC = bic
+	} else {
+		a.FinInstnID.BICFI = bic
+	}
+	return a
+}
@@ -0,0 +1,158 @@
+package iso20022
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+	"github.com/stretchr/testify/assert"
+)
+
+var testDebtor = Party{Name: "Corp Ltd", IBAN: "HU93116000060000000012345676", BIC: "CIBHHUHBXXX"}
+
+func genHCT(t *testing.T, amount int, invoiceID, msg string) qr.Code {
+	c, err := qr.NewPaymentSend("OTPVHUHB", "Shop Ltd", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	assert.NoError(t, c.HUFAmount(amount))
+	assert.NoError(t, c.InvoiceID(invoiceID))
+	assert.NoError(t, c.Message(msg))
+	assert.NoError(t, c.ValidUntil(time.Now().Add(time.Hour)))
+	return *c
+}
+
+func TestPain001V03(t *testing.T) {
+	created := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	b, err := Pain001([]Transfer{{Code: genHCT(t, 1500, "INV-1", "Thanks")}}, Batch{MessageID: "B1", Created: created, Debtor: testDebtor})
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">
+  <CstmrCdtTrfInitn>
+    <GrpHdr>
+      <MsgId>B1</MsgId>
+      <CreDtTm>2024-01-02T10:00:00Z</CreDtTm>
+      <NbOfTxs>1</NbOfTxs>
+      <CtrlSum>1500</CtrlSum>
+      <InitgPty>
+        <Nm>Corp Ltd</Nm>
+      </InitgPty>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>B1-1</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <NbOfTxs>1</NbOfTxs>
+      <CtrlSum>1500</CtrlSum>
+      <ReqdExctnDt>2024-01-02</ReqdExctnDt>
+      <Dbtr>
+        <Nm>Corp Ltd</Nm>
+      </Dbtr>
+      <DbtrAcct>
+        <Id>
+          <IBAN>HU93116000060000000012345676</IBAN>
+        </Id>
+      </DbtrAcct>
+      <DbtrAgt>
+        <FinInstnId>
+          <BIC>CIBHHUHBXXX</BIC>
+        </FinInstnId>
+      </DbtrAgt>
+      <ChrgBr>SLEV</ChrgBr>
+      <CdtTrfTxInf>
+        <PmtId>
+          <EndToEndId>INV-1</EndToEndId>
+        </PmtId>
+        <Amt>
+          <InstdAmt Ccy="HUF">1500</InstdAmt>
+        </Amt>
+        <CdtrAgt>
+          <FinInstnId>
+            <BIC>OTPVHUHBXXX</BIC>
+          </FinInstnId>
+        </CdtrAgt>
+        <Cdtr>
+          <Nm>Shop Ltd</Nm>
+        </Cdtr>
+        <CdtrAcct>
+          <Id>
+            <IBAN>HU42117730161111101800000000</IBAN>
+          </Id>
+        </CdtrAcct>
+        <RmtInf>
+          <Ustrd>INV-1 Thanks</Ustrd>
+        </RmtInf>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`, string(b))
+}
+
+func TestPain001V09Grouping(t *testing.T) {
+	execution := time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)
+	transfers := []Transfer{
+		{Code: genHCT(t, 100, "", "")},
+		{Code: genHCT(t, 200, "INV-2", ""), Execution: execution.AddDate(0, 0, 1)},
+		{Code: genHCT(t, 300, "INV-3", ""), EndToEndID: "E2E-3"},
+	}
+
+	b, err := Pain001(transfers, Batch{MessageID: strings.Repeat("M", 35), Version: Pain001V09, Execution: execution, Debtor: testDebtor})
+	assert.NoError(t, err)
+
+	xml := string(b)
+	assert.Contains(t, xml, `xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"`)
+	assert.Contains(t, xml, "<NbOfTxs>3</NbOfTxs>\n      <CtrlSum>600</CtrlSum>")
+	assert.Contains(t, xml, "<PmtInfId>"+strings.Repeat("M", 33)+"-1</PmtInfId>\n      <PmtMtd>TRF</PmtMtd>\n      <NbOfTxs>2</NbOfTxs>\n      <CtrlSum>400</CtrlSum>\n      <ReqdExctnDt>\n        <Dt>2024-01-05</Dt>")
+	assert.Contains(t, xml, "-2</PmtInfId>\n      <PmtMtd>TRF</PmtMtd>\n      <NbOfTxs>1</NbOfTxs>\n      <CtrlSum>200</CtrlSum>\n      <ReqdExctnDt>\n        <Dt>2024-01-06</Dt>")
+	assert.Contains(t, xml, "<BICFI>CIBHHUHBXXX</BICFI>")
+	assert.Contains(t, xml, "<EndToEndId>NOTPROVIDED</EndToEndId>")
+	assert.Contains(t, xml, "<EndToEndId>E2E-3</EndToEndId>")
+	assert.NotContains(t, xml, "<BIC>")
+}
+
+func TestPain001Errors(t *testing.T) {
+	valid := []Transfer{{Code: genHCT(t, 100, "", "")}}
+	batch := Batch{MessageID: "B1", Debtor: testDebtor}
+
+	_, err := Pain001(valid, Batch{MessageID: "B1", Version: "pain.001.001.02", Debtor: testDebtor})
+	assert.EqualError(t, err, "unsupported version: pain.001.001.02")
+
+	_, err = Pain001(valid, Batch{Debtor: testDebtor})
+	assert.EqualError(t, err, "message id is required (35 chars max)")
+
+	_, err = Pain001(valid, Batch{MessageID: "B1"})
+	assert.EqualError(t, err, "debtor name, iban and bic are required")
+
+	_, err = Pain001(nil, batch)
+	assert.EqualError(t, err, "no transfer in the batch")
+
+	c := genHCT(t, 100, "", "")
+	c.Kind = qr.KindRTP
+	_, err = Pain001(append(valid, Transfer{Code: c}), batch)
+	assert.EqualError(t, err, "transfer 2: only HCT codes could be exported")
+
+	noAmount, _ := qr.NewPaymentSend("OTPVHUHB", "Shop Ltd", "HU42117730161111101800000000")
+	_, err = Pain001([]Transfer{{Code: *noAmount}}, batch)
+	assert.EqualError(t, err, "transfer 1: amount is required")
+
+	assert.NoError(t, noAmount.HUFAmount(1))
+	assert.NoError(t, noAmount.Set("valid", "20200101000000+1"))
+	_, err = Pain001([]Transfer{{Code: *noAmount}}, batch)
+	assert.EqualError(t, err, "transfer 1: the code is expired")
+}
+
+func TestRemittance(t *testing.T) {
+	c := genHCT(t, 1, "", strings.Repeat("a", 70))
+	assert.NoError(t, c.InvoiceID(strings.Repeat("b", 35)))
+	assert.NoError(t, c.ShopID("x"))
+	assert.Equal(t, strings.Repeat("b", 35)+" "+strings.Repeat("a", 70), remittance(c))
+}
+
+func TestLookupPain001Version(t *testing.T) {
+	for s, expected := range map[string]Pain001Version{"": Pain001V03, "03": Pain001V03, "pain.001.001.09": Pain001V09, "09": Pain001V09} {
+		v, err := LookupPain001Version(s)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, v)
+	}
+
+	_, err := LookupPain001Version("10")
+	assert.EqualError(t, err, "unsupported version: 10")
+}
@@ -0,0 +1,180 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+const (
+	pain013Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.013.001.07"
+	dateTimeLayout   = "2006-01-02T15:04:05Z07:00"
+	dateLayout       = "2006-01-02"
+	notProvided      = "NOTPROVIDED"
+)
+
+// Party is an account holder
+type Party struct {
+	Name string
+	IBAN string
+	BIC  string
+}
+
+// RequestToPay contains the payee side of the request, the payer data comes from the RTP code
+type RequestToPay struct {
+	MessageID  string    // Required, unique identifier of the message (35 chars max)
+	EndToEndID string    // Passed to the payee with the payment, NOTPROVIDED if empty
+	Created    time.Time // Now if empty
+	Execution  time.Time // Requested execution date, the creation day if empty
+	Payee      Party     // Required, the creditor
+	Amount     int       // In HUF, used only if the code has no amount
+}
+
+type document013 struct {
+	XMLName xml.Name `xml:"Document"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Request struct {
+		GrpHdr struct {
+			MsgID    string `xml:"MsgId"`
+			CreDtTm  string
+			NbOfTxs  int
+			InitgPty name
+		}
+		PmtInf struct {
+			PmtInfID    string `xml:"PmtInfId"`
+			PmtMtd      string
+			ReqdExctnDt struct {
+				Dt string
+			}
+			XpryDt *struct {
+				DtTm string
+			} `xml:",omitempty"`
+			Dbtr     name
+			DbtrAcct account
+			DbtrAgt  agent
+			CdtTrfTx struct {
+				PmtID struct {
+					EndToEndID string `xml:"EndToEndId"`
+				} `xml:"PmtId"`
+				Amt struct {
+					InstdAmt instructedAmount
+				}
+				ChrgBr   string
+				CdtrAgt  agent
+				Cdtr     name
+				CdtrAcct account
+				Purp     *struct {
+					Cd string
+				} `xml:",omitempty"`
+				RmtInf *struct {
+					Ustrd string
+				} `xml:",omitempty"`
+			}
+		}
+	} `xml:"CdtrPmtActvtnReq"`
+}
+
+type name struct {
+	Nm string
+}
+
+type account struct {
+	ID struct {
+		IBAN string
+	} `xml:"Id"`
+}
+
+type agent struct {
+	FinInstnID struct {
+		BICFI string
+	} `xml:"FinInstnId"`
+}
+
+type instructedAmount struct {
+	Ccy   string `xml:",attr"`
+	Value string `xml:",chardata"`
+}
+
+// Pain013 generates a pain.013.001.07 creditor payment activation request from a scanned RTP code
+// The code holds the payer (debtor), the amount, the purpose and the message, the expiration is the validity of the code.
+func Pain013(c qr.Code, req RequestToPay) ([]byte, error) {
+	if c.Kind != qr.KindRTP {
+		return nil, errors.New("only RTP codes could be requested")
+	}
+
+	if req.MessageID == "" || len(req.MessageID) > 35 {
+		return nil, errors.New("message id is required (35 chars max)")
+	}
+
+	if req.Payee.Name == "" || req.Payee.IBAN == "" || req.Payee.BIC == "" {
+		return nil, errors.New("payee name, iban and bic are required")
+	}
+
+	currency, value := c.Amount.Currency(), c.Amount.Decimal()
+	if c.Get("amount") == "" {
+		if req.Amount <= 0 {
+			return nil, errors.New("amount is required")
+		}
+
+		currency, value = "HUF", strconv.Itoa(req.Amount)
+	}
+	if currency == "" {
+		currency = "HUF"
+	}
+
+	created := req.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+
+	execution := req.Execution
+	if execution.IsZero() {
+		execution = created
+	}
+
+	endToEndID := req.EndToEndID
+	if endToEndID == "" {
+		endToEndID = notProvided
+	}
+
+	doc := document013{Xmlns: pain013Namespace}
+	hdr := &doc.Request.GrpHdr
+	hdr.MsgID = req.MessageID
+	hdr.CreDtTm = created.Format(dateTimeLayout)
+	hdr.NbOfTxs = 1
+	hdr.InitgPty.Nm = req.Payee.Name
+
+	pmt := &doc.Request.PmtInf
+	pmt.PmtInfID = req.MessageID
+	pmt.PmtMtd = "TRF"
+	pmt.ReqdExctnDt.Dt = execution.Format(dateLayout)
+	if expiry := c.Expiry(); !expiry.IsZero() {
+		pmt.XpryDt = &struct{ DtTm string }{expiry.Format(dateTimeLayout)}
+	}
+	pmt.Dbtr.Nm = c.Name
+	pmt.DbtrAcct.ID.IBAN = c.IBAN
+	pmt.DbtrAgt.FinInstnID.BICFI = c.BIC
+
+	tx := &pmt.CdtTrfTx
+	tx.PmtID.EndToEndID = endToEndID
+	tx.Amt.InstdAmt = instructedAmount{Ccy: currency, Value: value}
+	tx.ChrgBr = "SLEV"
+	tx.CdtrAgt.FinInstnID.BICFI = req.Payee.BIC
+	tx.Cdtr.Nm = req.Payee.Name
+	tx.CdtrAcct.ID.IBAN = req.Payee.IBAN
+	if purpose := c.Get("purpose"); purpose != "" {
+		tx.Purp = &struct{ Cd string }{purpose}
+	}
+	if msg := c.Get("message"); msg != "" {
+		tx.RmtInf = &struct{ Ustrd string }{msg}
+	}
+
+	b, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
@@ -0,0 +1,120 @@
+package iso20022
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPayee = Party{Name: "Shop Ltd", IBAN: "HU42117730161111101800000000", BIC: "OTPVHUHBXXX"}
+
+func genRTP(t *testing.T) qr.Code {
+	c, err := qr.NewPaymentRequest("CIBHHUHB", "Test Payer", "HU93116000060000000012345676")
+	assert.NoError(t, err)
+	assert.NoError(t, c.Set("valid", "20240102150405+1"))
+	return *c
+}
+
+func TestPain013(t *testing.T) {
+	c := genRTP(t)
+	assert.NoError(t, c.HUFAmount(1500))
+	assert.NoError(t, c.Purpose("GDDS"))
+	assert.NoError(t, c.Message("Invoice 42"))
+
+	created := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	b, err := Pain013(c, RequestToPay{MessageID: "MSG1", EndToEndID: "E2E1", Created: created, Payee: testPayee})
+	assert.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.013.001.07">
+  <CdtrPmtActvtnReq>
+    <GrpHdr>
+      <MsgId>MSG1</MsgId>
+      <CreDtTm>2024-01-02T10:00:00Z</CreDtTm>
+      <NbOfTxs>1</NbOfTxs>
+      <InitgPty>
+        <Nm>Shop Ltd</Nm>
+      </InitgPty>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>MSG1</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <ReqdExctnDt>
+        <Dt>2024-01-02</Dt>
+      </ReqdExctnDt>
+      <XpryDt>
+        <DtTm>2024-01-02T15:04:05+01:00</DtTm>
+      </XpryDt>
+      <Dbtr>
+        <Nm>Test Payer</Nm>
+      </Dbtr>
+      <DbtrAcct>
+        <Id>
+          <IBAN>HU93116000060000000012345676</IBAN>
+        </Id>
+      </DbtrAcct>
+      <DbtrAgt>
+        <FinInstnId>
+          <BICFI>CIBHHUHBXXX</BICFI>
+        </FinInstnId>
+      </DbtrAgt>
+      <CdtTrfTx>
+        <PmtId>
+          <EndToEndId>E2E1</EndToEndId>
+        </PmtId>
+        <Amt>
+          <InstdAmt Ccy="HUF">1500</InstdAmt>
+        </Amt>
+        <ChrgBr>SLEV</ChrgBr>
+        <CdtrAgt>
+          <FinInstnId>
+            <BICFI>OTPVHUHBXXX</BICFI>
+          </FinInstnId>
+        </CdtrAgt>
+        <Cdtr>
+          <Nm>Shop Ltd</Nm>
+        </Cdtr>
+        <CdtrAcct>
+          <Id>
+            <IBAN>HU42117730161111101800000000</IBAN>
+          </Id>
+        </CdtrAcct>
+        <Purp>
+          <Cd>GDDS</Cd>
+        </Purp>
+        <RmtInf>
+          <Ustrd>Invoice 42</Ustrd>
+        </RmtInf>
+      </CdtTrfTx>
+    </PmtInf>
+  </CdtrPmtActvtnReq>
+</Document>`, string(b))
+}
+
+func TestPain013Defaults(t *testing.T) {
+	c := genRTP(t)
+	b, err := Pain013(c, RequestToPay{MessageID: "MSG1", Payee: testPayee, Amount: 990})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `<InstdAmt Ccy="HUF">990</InstdAmt>`)
+	assert.Contains(t, string(b), `<EndToEndId>NOTPROVIDED</EndToEndId>`)
+	assert.NotContains(t, string(b), `<Purp>`)
+	assert.NotContains(t, string(b), `<RmtInf>`)
+}
+
+func TestPain013Errors(t *testing.T) {
+	c := genRTP(t)
+
+	_, err := Pain013(c, RequestToPay{Payee: testPayee, Amount: 1})
+	assert.EqualError(t, err, "message id is required (35 chars max)")
+
+	_, err = Pain013(c, RequestToPay{MessageID: "MSG1", Amount: 1})
+	assert.EqualError(t, err, "payee name, iban and bic are required")
+
+	_, err = Pain013(c, RequestToPay{MessageID: "MSG1", Payee: testPayee})
+	assert.EqualError(t, err, "amount is required")
+
+	c.Kind = qr.KindHCT
+	_, err = Pain013(c, RequestToPay{MessageID: "MSG1", Payee: testPayee, Amount: 1})
+	assert.EqualError(t, err, "only RTP codes could be requested")
+}
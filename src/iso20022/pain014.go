@@ -0,0 +1,146 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Status codes of the status report
+const (
+	StatusAccepted = "ACCP" // Accepted by the payer
+	StatusPending  = "PDNG"
+	StatusRejected = "RJCT"
+)
+
+// Reason of a status
+type Reason struct {
+	Code           string // ISO external status reason code, e.g. AC04
+	Proprietary    string
+	AdditionalInfo []string
+}
+
+// TransactionStatus of a single request
+type TransactionStatus struct {
+	StatusID           string
+	OriginalEndToEndID string
+	Status             string
+	Reasons            []Reason
+}
+
+// StatusReport is a parsed pain.014 creditor payment activation request status report
+type StatusReport struct {
+	MessageID         string
+	Created           time.Time
+	OriginalMessageID string
+	GroupStatus       string // Could be empty, then the transaction statuses are the relevant ones
+	Reasons           []Reason
+	Transactions      []TransactionStatus
+}
+
+type document014 struct {
+	XMLName xml.Name
+	Report  *struct {
+		GrpHdr struct {
+			MsgID   string `xml:"MsgId"`
+			CreDtTm string
+		}
+		OrgnlGrpInfAndSts struct {
+			OrgnlMsgID string `xml:"OrgnlMsgId"`
+			GrpSts     string
+			StsRsnInf  []statusReason
+		}
+		OrgnlPmtInfAndSts []struct {
+			TxInfAndSts []struct {
+				StsID           string `xml:"StsId"`
+				OrgnlEndToEndID string `xml:"OrgnlEndToEndId"`
+				TxSts           string
+				StsRsnInf       []statusReason
+			}
+		}
+	} `xml:"CdtrPmtActvtnReqStsRpt"`
+}
+
+type statusReason struct {
+	Rsn struct {
+		Cd    string
+		Prtry string
+	}
+	AddtlInf []string
+}
+
+// ParsePain014 reads a pain.014 status report, any pain.014 version is accepted
+func ParsePain014(b []byte) (*StatusReport, error) {
+	var doc document014
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.XMLName.Local != "Document" || !strings.Contains(doc.XMLName.Space, "pain.014") || doc.Report == nil {
+		return nil, errors.New("not a pain.014 document")
+	}
+
+	report := &StatusReport{
+		MessageID:         doc.Report.GrpHdr.MsgID,
+		OriginalMessageID: doc.Report.OrgnlGrpInfAndSts.OrgnlMsgID,
+		GroupStatus:       doc.Report.OrgnlGrpInfAndSts.GrpSts,
+		Reasons:           reasons(doc.Report.OrgnlGrpInfAndSts.StsRsnInf),
+	}
+
+	if doc.Report.GrpHdr.CreDtTm != "" {
+		created, err := parseDateTime(doc.Report.GrpHdr.CreDtTm)
+		if err != nil {
+			return nil, err
+		}
+		report.Created = created
+	}
+
+	for _, pmt := range doc.Report.OrgnlPmtInfAndSts {
+		for _, tx := range pmt.TxInfAndSts {
+			report.Transactions = append(report.Transactions, TransactionStatus{
+				StatusID:           tx.StsID,
+				OriginalEndToEndID: tx.OrgnlEndToEndID,
+				Status:             tx.TxSts,
+				Reasons:            reasons(tx.StsRsnInf),
+			})
+		}
+	}
+	return report, nil
+}
+
+// Accepted returns true if the group or all the transactions are accepted
+func (r StatusReport) Accepted() bool {
+	if r.GroupStatus != "" {
+		return r.GroupStatus == StatusAccepted
+	}
+
+	for _, tx := range r.Transactions {
+		if tx.Status != StatusAccepted {
+			return false
+		}
+	}
+	return len(r.Transactions) > 0
+}
+
+func reasons(infos []statusReason) []Reason {
+	var list []Reason
+	for _, info := range infos {
+		list = append(list, Reason{
+			Code:           info.Rsn.Cd,
+			Proprietary:    info.Rsn.Prtry,
+			AdditionalInfo: info.AddtlInf,
+		})
+	}
+	return list
+}
+
+// parseDateTime accepts the ISO date time with or without the timezone and the fraction of the seconds
+func parseDateTime(s string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid date time: " + s)
+}
@@ -0,0 +1,47 @@
+package iso20022
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePain014(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/pain014.xml")
+	assert.NoError(t, err)
+
+	report, err := ParsePain014(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "STS1", report.MessageID)
+	assert.Equal(t, "MSG1", report.OriginalMessageID)
+	assert.True(t, report.Created.Equal(time.Date(2024, 1, 2, 9, 5, 0, 123000000, time.UTC)))
+	assert.Equal(t, "", report.GroupStatus)
+	assert.Equal(t, []TransactionStatus{
+		{StatusID: "S1", OriginalEndToEndID: "E2E1", Status: StatusRejected, Reasons: []Reason{{Code: "AC04", AdditionalInfo: []string{"Closed account"}}}},
+		{StatusID: "S2", OriginalEndToEndID: "E2E2", Status: StatusAccepted},
+	}, report.Transactions)
+	assert.False(t, report.Accepted())
+
+	report.Transactions = report.Transactions[1:]
+	assert.True(t, report.Accepted())
+
+	report.GroupStatus = StatusPending
+	assert.False(t, report.Accepted())
+}
+
+func TestParsePain014Errors(t *testing.T) {
+	_, err := ParsePain014([]byte(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.013.001.07"><CdtrPmtActvtnReq/></Document>`))
+	assert.EqualError(t, err, "not a pain.014 document")
+
+	_, err = ParsePain014([]byte(`<Document`))
+	assert.Error(t, err)
+
+	_, err = ParsePain014([]byte(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.014.001.07"><CdtrPmtActvtnReqStsRpt><GrpHdr><CreDtTm>yesterday</CreDtTm></GrpHdr></CdtrPmtActvtnReqStsRpt></Document>`))
+	assert.EqualError(t, err, "invalid date time: yesterday")
+
+	report, err := ParsePain014([]byte(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.014.001.07"><CdtrPmtActvtnReqStsRpt><OrgnlGrpInfAndSts><GrpSts>ACCP</GrpSts></OrgnlGrpInfAndSts></CdtrPmtActvtnReqStsRpt></Document>`))
+	assert.NoError(t, err)
+	assert.True(t, report.Accepted())
+}
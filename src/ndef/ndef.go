@@ -0,0 +1,234 @@
+package ndef
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// TNF type name format of a record
+type TNF byte
+
+// Type name formats
+const (
+	TNFEmpty       TNF = 0x00
+	TNFWellKnown   TNF = 0x01
+	TNFMedia       TNF = 0x02
+	TNFAbsoluteURI TNF = 0x03
+	TNFExternal    TNF = 0x04
+	TNFUnknown     TNF = 0x05
+	TNFUnchanged   TNF = 0x06
+)
+
+// Record header flags
+const (
+	flagMB = 0x80 // Message begin
+	flagME = 0x40 // Message end
+	flagCF = 0x20 // Chunk
+	flagSR = 0x10 // Short record
+	flagIL = 0x08 // ID length present
+)
+
+// PaymentType is the NFC Forum external type of the payment code records
+const PaymentType = "gerifield.github.io:mnbqr"
+
+// uriPrefixes are the abbreviations of the URI record, the index is the identifier code
+var uriPrefixes = []string{
+	"", "http://www.", "https://www.", "http://", "https://", "tel:", "mailto:", "ftp://anonymous:anonymous@",
+	"ftp://ftp.", "ftps://", "sftp://", "smb://", "nfs://", "ftp://", "dav://", "news:", "telnet://", "imap:",
+	"rtsp://", "urn:", "pop:", "sip:", "sips:", "tftp:", "btspp://", "btl2cap://", "btgoep://", "tcpobex://",
+	"irdaobex://", "file://", "urn:epc:id:", "urn:epc:tag:", "urn:epc:pat:", "urn:epc:raw:", "urn:epc:", "urn:nfc:",
+}
+
+// Record is a single NDEF record
+type Record struct {
+	TNF     TNF
+	Type    []byte
+	ID      []byte
+	Payload []byte
+}
+
+// Message is a list of records which are written to a tag together
+type Message []Record
+
+// NewURIRecord creates a well known URI record with the longest matching prefix abbreviated
+func NewURIRecord(uri string) Record {
+	code := 0
+	for i, p := range uriPrefixes {
+		if p != "" && strings.HasPrefix(uri, p) && len(p) > len(uriPrefixes[code]) {
+			code = i
+		}
+	}
+
+	return Record{
+		TNF:     TNFWellKnown,
+		Type:    []byte("U"),
+		Payload: append([]byte{byte(code)}, uri[len(uriPrefixes[code]):]...),
+	}
+}
+
+// NewExternalRecord creates an external type record, the type should be in the domain:type form
+func NewExternalRecord(typ string, payload []byte) Record {
+	return Record{
+		TNF:     TNFExternal,
+		Type:    []byte(strings.ToLower(typ)),
+		Payload: payload,
+	}
+}
+
+// URI returns the URI of a well known URI record
+func (r Record) URI() (string, error) {
+	if r.TNF != TNFWellKnown || string(r.Type) != "U" {
+		return "", errors.New("not an URI record")
+	}
+
+	if len(r.Payload) == 0 || int(r.Payload[0]) >= len(uriPrefixes) {
+		return "", errors.New("invalid URI record")
+	}
+	return uriPrefixes[r.Payload[0]] + string(r.Payload[1:]), nil
+}
+
+// MarshalBinary encodes the message, the records with less than 256 bytes payload are short records
+func (m Message) MarshalBinary() ([]byte, error) {
+	if len(m) == 0 {
+		return nil, errors.New("empty message")
+	}
+
+	var buf bytes.Buffer
+	for i, r := range m {
+		if len(r.Type) > 255 || len(r.ID) > 255 {
+			return nil, errors.New("record type or id is too long")
+		}
+
+		header := byte(r.TNF) & 0x07
+		if i == 0 {
+			header |= flagMB
+		}
+		if i == len(m)-1 {
+			header |= flagME
+		}
+		if len(r.Payload) < 256 {
+			header |= flagSR
+		}
+		if len(r.ID) > 0 {
+			header |= flagIL
+		}
+
+		buf.WriteByte(header)
+		buf.WriteByte(byte(len(r.Type)))
+		if header&flagSR != 0 {
+			buf.WriteByte(byte(len(r.Payload)))
+		} else {
+			_ = binary.Write(&buf, binary.BigEndian, uint32(len(r.Payload)))
+		}
+		if len(r.ID) > 0 {
+			buf.WriteByte(byte(len(r.ID)))
+		}
+		buf.Write(r.Type)
+		buf.Write(r.ID)
+		buf.Write(r.Payload)
+	}
+	return buf.Bytes(), nil
+}
+
+// Parse decodes a binary NDEF message, chunked records are not supported
+func Parse(b []byte) (Message, error) {
+	var m Message
+	for len(b) > 0 {
+		header := b[0]
+		if len(m) == 0 && header&flagMB == 0 {
+			return nil, errors.New("missing message begin flag")
+		}
+		if header&flagCF != 0 {
+			return nil, errors.New("chunked records are not supported")
+		}
+
+		pos := 2
+		if len(b) < pos {
+			return nil, errors.New("truncated record header")
+		}
+		typeLen := int(b[1])
+
+		var payloadLen int
+		if header&flagSR != 0 {
+			if len(b) < pos+1 {
+				return nil, errors.New("truncated record header")
+			}
+			payloadLen = int(b[pos])
+			pos++
+		} else {
+			if len(b) < pos+4 {
+				return nil, errors.New("truncated record header")
+			}
+			payloadLen = int(binary.BigEndian.Uint32(b[pos:]))
+			pos += 4
+		}
+
+		idLen := 0
+		if header&flagIL != 0 {
+			if len(b) < pos+1 {
+				return nil, errors.New("truncated record header")
+			}
+			idLen = int(b[pos])
+			pos++
+		}
+
+		if payloadLen < 0 || len(b)-pos < typeLen+idLen+payloadLen {
+			return nil, errors.New("truncated record")
+		}
+
+		r := Record{TNF: TNF(header & 0x07)}
+		r.Type = b[pos : pos+typeLen]
+		pos += typeLen
+		if idLen > 0 {
+			r.ID = b[pos : pos+idLen]
+			pos += idLen
+		}
+		r.Payload = b[pos : pos+payloadLen]
+		pos += payloadLen
+
+		m = append(m, r)
+		b = b[pos:]
+		if header&flagME != 0 {
+			if len(b) > 0 {
+				return nil, fmt.Errorf("%d bytes after the message end", len(b))
+			}
+			return m, nil
+		}
+	}
+	return nil, errors.New("missing message end flag")
+}
+
+// PaymentMessage wraps the validated content of the payment code into an external type record
+// With an uri (e.g. a bank app or a payment page link) it's added as the first record, the phones open it without an app.
+func PaymentMessage(f qr.Format, p qr.Payload, uri string) (Message, error) {
+	content, err := f.Encode(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Message
+	if uri != "" {
+		m = append(m, NewURIRecord(uri))
+	}
+	return append(m, NewExternalRecord(PaymentType, []byte(content))), nil
+}
+
+// ParsePayment finds the payment record in the binary message and detects its format
+func ParsePayment(b []byte) (qr.Format, qr.Payload, error) {
+	m, err := Parse(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, r := range m {
+		if r.TNF == TNFExternal && string(r.Type) == PaymentType {
+			return qr.Detect(string(r.Payload))
+		}
+	}
+	return nil, nil, errors.New("no payment record in the message")
+}
@@ -0,0 +1,114 @@
+package ndef
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+	"github.com/stretchr/testify/assert"
+)
+
+const epcExample = "BCD\n002\n1\nSCT\nBFSWDE33BER\nWikimedia Foerdergesellschaft\nDE33100205000001194700\nEUR123.45\n\n\nSpende fuer Wikipedia"
+
+func TestURIRecord(t *testing.T) {
+	r := NewURIRecord("https://www.example.com/pay")
+	assert.Equal(t, append([]byte{0x02}, "example.com/pay"...), r.Payload)
+
+	uri, err := r.URI()
+	assert.NoError(t, err)
+	assert.Equal(t, "https://www.example.com/pay", uri)
+
+	r = NewURIRecord("urn:epc:id:something")
+	assert.Equal(t, byte(0x1E), r.Payload[0])
+
+	r = NewURIRecord("myapp://pay")
+	assert.Equal(t, byte(0x00), r.Payload[0])
+
+	_, err = NewExternalRecord("a:b", nil).URI()
+	assert.EqualError(t, err, "not an URI record")
+	_, err = Record{TNF: TNFWellKnown, Type: []byte("U"), Payload: []byte{0x40}}.URI()
+	assert.EqualError(t, err, "invalid URI record")
+}
+
+func TestMarshalBinary(t *testing.T) {
+	b, err := Message{NewURIRecord("https://example.com")}.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte{0xD1, 0x01, 0x0C, 'U', 0x04}, "example.com"...), b)
+
+	_, err = Message{}.MarshalBinary()
+	assert.EqualError(t, err, "empty message")
+
+	// Long record with an ID
+	m := Message{
+		NewURIRecord("https://example.com"),
+		{TNF: TNFMedia, Type: []byte("text/plain"), ID: []byte("1"), Payload: bytes.Repeat([]byte("a"), 300)},
+	}
+	b, err = m.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0x91), b[0])
+	assert.Equal(t, []byte{0x4A, 0x0A, 0x00, 0x00, 0x01, 0x2C, 0x01}, b[16:23])
+
+	parsed, err := Parse(b)
+	assert.NoError(t, err)
+	assert.Equal(t, m, parsed)
+}
+
+func TestParseErrors(t *testing.T) {
+	for msg, b := range map[string][]byte{
+		"missing message begin flag":        {0x11, 0x01, 0x00, 'U'},
+		"chunked records are not supported": {0xB1, 0x01, 0x00, 'U'},
+		"truncated record header":           {0xD1},
+		"truncated record":                  {0xD1, 0x01, 0x05, 'U'},
+		"missing message end flag":          {0x91, 0x01, 0x00, 'U'},
+		"1 bytes after the message end":     {0xD1, 0x01, 0x00, 'U', 0x00},
+		"no payment record in the message":  {0xD1, 0x01, 0x00, 'U'},
+	} {
+		_, err := Parse(b)
+		if msg == "no payment record in the message" {
+			assert.NoError(t, err)
+			_, _, err = ParsePayment(b)
+		}
+		assert.EqualError(t, err, msg)
+	}
+}
+
+func TestPaymentMessage(t *testing.T) {
+	f, p, err := qr.Detect(epcExample)
+	assert.NoError(t, err)
+
+	m, err := PaymentMessage(f, p, "https://example.com/pay/1")
+	assert.NoError(t, err)
+	assert.Len(t, m, 2)
+	assert.Equal(t, TNFExternal, m[1].TNF)
+	assert.Equal(t, PaymentType, string(m[1].Type))
+
+	b, err := m.MarshalBinary()
+	assert.NoError(t, err)
+
+	pf, pp, err := ParsePayment(b)
+	assert.NoError(t, err)
+	assert.Equal(t, "EPC", pf.Name())
+	assert.Equal(t, epcExample, pp.String())
+
+	m, err = PaymentMessage(f, p, "")
+	assert.NoError(t, err)
+	assert.Len(t, m, 1)
+
+	_, err = PaymentMessage(f, f.New(), "")
+	assert.Error(t, err)
+}
+
+func TestPaymentMessageMNB(t *testing.T) {
+	c, err := qr.NewPaymentSend("CIBHHUHB", "Test User", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+
+	f, _ := qr.LookupFormat("HCT")
+	_, err = PaymentMessage(f, c, "")
+	assert.EqualError(t, err, "negative validity period")
+
+	assert.NoError(t, c.Set("valid", "20991231235959+1"))
+	m, err := PaymentMessage(f, c, "")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(m[0].Payload), "HCT\n"))
+}
@@ -0,0 +1,79 @@
+package qr
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Amount for payment (optional)
+type amount struct {
+	currency string
+	total    int // In the smallest unit of the currency, see decimals
+	decimals int // Number of the decimal digits (0 for HUF, 2 for EUR)
+}
+
+// String .
+func (a amount) String() string {
+	currency := a.currency
+	if currency == "" {
+		currency = "HUF"
+	}
+	return currency + a.Decimal()
+}
+
+// Decimal returns the amount without the currency with "." as a decimal separator
+func (a amount) Decimal() string {
+	if a.decimals == 0 {
+		return strconv.Itoa(a.total)
+	}
+
+	s := strconv.Itoa(a.total)
+	if len(s) <= a.decimals {
+		s = strings.Repeat("0", a.decimals-len(s)+1) + s
+	}
+	return s[:len(s)-a.decimals] + "." + s[len(s)-a.decimals:]
+}
+
+// Currency of the amount
+func (a amount) Currency() string {
+	return a.currency
+}
+
+// ParseDecimal parses a positive decimal number with at most the given decimal digits into the smallest unit
+func ParseDecimal(s string, decimals int) (int, error) {
+	whole, fraction := s, ""
+	if i := strings.IndexAny(s, ".,"); i >= 0 {
+		whole, fraction = s[:i], s[i+1:]
+	}
+
+	if whole == "" || len(fraction) > decimals || (len(fraction) == 0 && len(whole) < len(s)) {
+		return 0, errors.New("invalid decimal amount")
+	}
+
+	for _, r := range whole + fraction {
+		if r < '0' || r > '9' {
+			return 0, errors.New("invalid decimal amount")
+		}
+	}
+
+	total, err := strconv.Atoi(whole + fraction + strings.Repeat("0", decimals-len(fraction)))
+	if err != nil {
+		return 0, errors.New("invalid decimal amount")
+	}
+	return total, nil
+}
+
+// checkCurrency accepts an empty or a three upper case letter ISO 4217 currency code
+func checkCurrency(currency string) error {
+	if currency != "" && len(currency) != 3 {
+		return errors.New("invalid currency")
+	}
+
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return errors.New("invalid currency")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package qr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmountString(t *testing.T) {
+	testTable := []struct {
+		input          amount
+		expectedOutput string
+	}{
+		{amount{}, "HUF0"},
+		{amount{currency: "HUF", total: 500}, "HUF500"},
+		{amount{currency: "EUR", total: 1250, decimals: 2}, "EUR12.50"},
+		{amount{currency: "EUR", total: 1, decimals: 2}, "EUR0.01"},
+		{amount{currency: "CZK", total: 100000, decimals: 2}, "CZK1000.00"},
+	}
+
+	for _, tt := range testTable {
+		assert.Equal(t, tt.expectedOutput, tt.input.String())
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	testTable := []struct {
+		input       string
+		decimals    int
+		expected    int
+		expectedErr bool
+	}{
+		{"12", 2, 1200, false},
+		{"12.5", 2, 1250, false},
+		{"12,50", 2, 1250, false},
+		{"0.01", 2, 1, false},
+		{"500", 0, 500, false},
+		{"12.505", 2, 0, true},
+		{"12.", 2, 0, true},
+		{".5", 2, 0, true},
+		{"-1", 2, 0, true},
+		{"1e3", 2, 0, true},
+		{"12.5", 0, 0, true},
+		{"", 2, 0, true},
+	}
+
+	for _, tt := range testTable {
+		total, err := ParseDecimal(tt.input, tt.decimals)
+		if tt.expectedErr {
+			assert.EqualError(t, err, "invalid decimal amount", tt.input)
+			continue
+		}
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, total, tt.input)
+	}
+}
@@ -0,0 +1,483 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/ulikunitz/xz/lzma"
+)
+
+const (
+	bySquareDictCap   = 128 * 1024
+	bySquareMaxAmount = 999999999999999 // 15 digits in cents
+
+	// First header byte: the type in the high nibble (0 for PAY) and the version in the low nibble
+	bySquareTypePay    = 0x0
+	bySquareVersion110 = 0x1 // Adds the beneficiary address fields
+)
+
+var (
+	// KindPayBySquare for the Slovak PAY by square codes
+	KindPayBySquare kind = "PAYBYSQUARE"
+
+	// PayBySquareCapacityOptions used for the PAY by square codes
+	PayBySquareCapacityOptions = CapacityOptions{
+		Level:      qrcode.Medium,
+		MaxVersion: 13,
+		MaxSize:    qrContentMaxSize,
+		Truncate:   TruncateNone,
+	}
+
+	bySquareEncoding = base32.HexEncoding.WithPadding(base32.NoPadding)
+	bySquareLZMA     = lzma.Properties{LC: 3, LP: 0, PB: 2}
+)
+
+// PayBySquareCode is a Slovak PAY by square code with a single payment order to a single account
+// Standard: https://www.sbaonline.sk/wp-content/uploads/2020/03/pay-by-square-specifications-1_1_0.pdf
+// The payload is tab separated, prefixed with a CRC32 checksum, LZMA compressed and base32hex encoded.
+type PayBySquareCode struct {
+	IBAN           string // Required
+	BIC            string
+	Name           string // Required, beneficiary name
+	Amount         amount // EUR by default
+	invoiceID      string
+	due            string
+	variableSymbol string
+	constantSymbol string
+	specificSymbol string
+	reference      string
+	message        string
+	address1       string
+	address2       string
+}
+
+type bySquareField struct {
+	Field
+	get func(p *PayBySquareCode) string
+	set func(p *PayBySquareCode, value string) error
+}
+
+var bySquareFields = []bySquareField{
+	{
+		Field: Field{Line: 0, Name: "iban", Type: "string", MaxLen: 34, Required: true, Usage: "beneficiary IBAN"},
+		get:   func(p *PayBySquareCode) string { return p.IBAN },
+		set:   setBySquareIBAN,
+	},
+	{
+		Field: Field{Line: 1, Name: "bic", Type: "string", MaxLen: 11, Usage: "`8` or `11` character"},
+		get:   func(p *PayBySquareCode) string { return p.BIC },
+		set:   setBySquareBIC,
+	},
+	{
+		Field: Field{Line: 2, Name: "name", Type: "string", MaxLen: 70, Required: true, Usage: "beneficiary name"},
+		get:   func(p *PayBySquareCode) string { return p.Name },
+		set:   bySquareText("name", 70, func(p *PayBySquareCode) *string { return &p.Name }),
+	},
+	{
+		Field: Field{Line: 3, Name: "amount", Type: "string", MaxLen: 15, Usage: "amount with at most two decimals"},
+		get:   getBySquareAmount,
+		set:   setBySquareAmount,
+	},
+	{
+		Field: Field{Line: 4, Name: "currency", Type: "string", MaxLen: 3, Usage: "ISO 4217 currency code, `EUR` by default"},
+		get:   getBySquareCurrency,
+		set:   setBySquareCurrency,
+	},
+	{
+		Field: Field{Line: 5, Name: "dueDate", Type: "string", MaxLen: 8, Usage: "due date in `20060102` format"},
+		get:   func(p *PayBySquareCode) string { return p.due },
+		set:   setBySquareDue,
+	},
+	{
+		Field: Field{Line: 6, Name: "variableSymbol", Type: "string", MaxLen: 10, Usage: "variable symbol, digits only"},
+		get:   func(p *PayBySquareCode) string { return p.variableSymbol },
+		set:   bySquareSymbol("variableSymbol", 10, func(p *PayBySquareCode) *string { return &p.variableSymbol }),
+	},
+	{
+		Field: Field{Line: 7, Name: "constantSymbol", Type: "string", MaxLen: 4, Usage: "constant symbol, digits only"},
+		get:   func(p *PayBySquareCode) string { return p.constantSymbol },
+		set:   bySquareSymbol("constantSymbol", 4, func(p *PayBySquareCode) *string { return &p.constantSymbol }),
+	},
+	{
+		Field: Field{Line: 8, Name: "specificSymbol", Type: "string", MaxLen: 10, Usage: "specific symbol, digits only"},
+		get:   func(p *PayBySquareCode) string { return p.specificSymbol },
+		set:   bySquareSymbol("specificSymbol", 10, func(p *PayBySquareCode) *string { return &p.specificSymbol }),
+	},
+	{
+		Field: Field{Line: 9, Name: "reference", Type: "string", MaxLen: 35, Usage: "SEPA originator's reference, instead of the symbols"},
+		get:   func(p *PayBySquareCode) string { return p.reference },
+		set:   bySquareText("reference", 35, func(p *PayBySquareCode) *string { return &p.reference }),
+	},
+	{
+		Field: Field{Line: 10, Name: "message", Type: "string", MaxLen: 140, Usage: "payment note"},
+		get:   func(p *PayBySquareCode) string { return p.message },
+		set:   bySquareText("message", 140, func(p *PayBySquareCode) *string { return &p.message }),
+	},
+	{
+		Field: Field{Line: 11, Name: "invoiceID", Type: "string", MaxLen: 10, Usage: "invoice ID of the whole document"},
+		get:   func(p *PayBySquareCode) string { return p.invoiceID },
+		set:   bySquareText("invoiceID", 10, func(p *PayBySquareCode) *string { return &p.invoiceID }),
+	},
+	{
+		Field: Field{Line: 12, Name: "address1", Type: "string", MaxLen: 70, Usage: "beneficiary address, first line"},
+		get:   func(p *PayBySquareCode) string { return p.address1 },
+		set:   bySquareText("address1", 70, func(p *PayBySquareCode) *string { return &p.address1 }),
+	},
+	{
+		Field: Field{Line: 13, Name: "address2", Type: "string", MaxLen: 70, Usage: "beneficiary address, second line"},
+		get:   func(p *PayBySquareCode) string { return p.address2 },
+		set:   bySquareText("address2", 70, func(p *PayBySquareCode) *string { return &p.address2 }),
+	},
+}
+
+// PayBySquareFields of the PAY by square code
+func PayBySquareFields() []Field {
+	fields := make([]Field, 0, len(bySquareFields))
+	for _, f := range bySquareFields {
+		set := f.set
+		field := f.Field
+		field.check = func(v string) error { return set(&PayBySquareCode{}, v) }
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// NewPayBySquarePayment creates a PAY by square payment order
+func NewPayBySquarePayment(bic string, name string, iban string) (*PayBySquareCode, error) {
+	p := &PayBySquareCode{}
+	for _, f := range []struct{ name, value string }{{"bic", bic}, {"name", name}, {"iban", iban}} {
+		if err := p.Set(f.name, f.value); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// PayBySquare converts the recipient, the amount and the message of the MNB code
+func (c Code) PayBySquare() (*PayBySquareCode, error) {
+	p, err := NewPayBySquarePayment(c.BIC, c.Name, c.IBAN)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Amount.total > 0 {
+		currency := c.Amount.Currency()
+		if currency == "" {
+			currency = "HUF"
+		}
+		if err := p.Set("currency", currency); err != nil {
+			return nil, err
+		}
+		if err := p.Set("amount", c.Amount.Decimal()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.Set("message", c.message); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParsePayBySquare decodes a base32hex PAY by square payload
+// Only the first payment and its first account are kept, the standing order and direct debit extensions are skipped.
+func ParsePayBySquare(content string) (*PayBySquareCode, error) {
+	data, err := bySquareDecode(content)
+	if err != nil {
+		return nil, err
+	}
+
+	values := strings.Split(data, "\t")
+	next := func() string {
+		if len(values) == 0 {
+			return ""
+		}
+		v := values[0]
+		values = values[1:]
+		return v
+	}
+
+	p := &PayBySquareCode{}
+	invoiceID := next()
+	if next() == "0" {
+		return nil, errors.New("no payment in the code")
+	}
+
+	next() // Payment type, the payment order and the standing order are handled the same way
+	raw := []struct{ name, value string }{
+		{"invoiceID", invoiceID},
+		{"amount", next()},
+		{"currency", next()},
+		{"dueDate", next()},
+		{"variableSymbol", next()},
+		{"constantSymbol", next()},
+		{"specificSymbol", next()},
+		{"reference", next()},
+		{"message", next()},
+	}
+
+	accounts, err := strconv.Atoi(next())
+	if err != nil || accounts < 1 {
+		return nil, errors.New("no account in the code")
+	}
+	raw = append(raw, struct{ name, value string }{"iban", next()}, struct{ name, value string }{"bic", next()})
+	skip := func(n int) {
+		for i := 0; i < n; i++ {
+			next()
+		}
+	}
+	skip((accounts - 1) * 2)
+
+	if next() == "1" { // Standing order extension
+		skip(4)
+	}
+	if next() == "1" { // Direct debit extension
+		skip(10)
+	}
+	raw = append(raw,
+		struct{ name, value string }{"name", next()},
+		struct{ name, value string }{"address1", next()},
+		struct{ name, value string }{"address2", next()},
+	)
+
+	for _, r := range raw {
+		if r.value == "" {
+			continue
+		}
+		if err := p.Set(r.name, r.value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Set a field by its name
+func (p *PayBySquareCode) Set(name string, value string) error {
+	for _, f := range bySquareFields {
+		if f.Name == name {
+			return f.set(p, value)
+		}
+	}
+	return fmt.Errorf("unknown field: %s", name)
+}
+
+// Get a field value by its name
+func (p PayBySquareCode) Get(name string) string {
+	for _, f := range bySquareFields {
+		if f.Name == name {
+			return f.get(&p)
+		}
+	}
+	return ""
+}
+
+// DueDate of the payment
+func (p *PayBySquareCode) DueDate(t time.Time) {
+	p.due = t.Format(spaydDate)
+}
+
+// Validate the required fields
+func (p PayBySquareCode) Validate() error {
+	for _, f := range bySquareFields {
+		if f.Required && f.get(&p) == "" {
+			return fmt.Errorf("%s is required", f.Name)
+		}
+	}
+	return nil
+}
+
+// String returns the encoded payload which should be in the QR code
+func (p PayBySquareCode) String() string {
+	content, _ := bySquareEncode(p.tabbed())
+	return content
+}
+
+// tabbed returns the uncompressed tab separated data
+func (p PayBySquareCode) tabbed() string {
+	return strings.Join([]string{
+		p.invoiceID,
+		"1", // Payment count
+		"1", // Payment order
+		p.Get("amount"),
+		p.Get("currency"),
+		p.due,
+		p.variableSymbol,
+		p.constantSymbol,
+		p.specificSymbol,
+		p.reference,
+		p.message,
+		"1", // Account count
+		p.IBAN,
+		p.BIC,
+		"0", // No standing order extension
+		"0", // No direct debit extension
+		p.Name,
+		p.address1,
+		p.address2,
+	}, "\t")
+}
+
+// bySquareEncode builds the header (type, version 1.1.0, document type and reserved 0), the data length and the raw
+// LZMA stream of the checksum prefixed data
+func bySquareEncode(data string) (string, error) {
+	var raw bytes.Buffer
+	_ = binary.Write(&raw, binary.LittleEndian, crc32.ChecksumIEEE([]byte(data)))
+	raw.WriteString(data)
+	if raw.Len() > 0xFFFF {
+		return "", errors.New("pay by square data is too large")
+	}
+
+	var compressed bytes.Buffer
+	w, err := lzma.WriterConfig{
+		Properties:   &bySquareLZMA,
+		DictCap:      bySquareDictCap,
+		SizeInHeader: true,
+		Size:         int64(raw.Len()),
+	}.NewWriter(&compressed)
+	if err != nil {
+		return "", err
+	}
+	size := raw.Len()
+	if _, err := raw.WriteTo(w); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+
+	out := []byte{bySquareTypePay<<4 | bySquareVersion110, 0x00, byte(size), byte(size >> 8)}
+	out = append(out, compressed.Bytes()[lzma.HeaderLen:]...) // Without the classic LZMA header
+	return bySquareEncoding.EncodeToString(out), nil
+}
+
+func bySquareDecode(content string) (string, error) {
+	b, err := bySquareEncoding.DecodeString(content)
+	if err != nil || len(b) < 4 {
+		return "", errors.New("invalid pay by square encoding")
+	}
+
+	// Any version is read, the newer ones only add fields to the end
+	if b[0]>>4 != bySquareTypePay {
+		return "", errors.New("not a pay by square payment")
+	}
+	size := int(b[2]) | int(b[3])<<8
+
+	// The raw stream gets the classic LZMA header back for the reader
+	header := make([]byte, lzma.HeaderLen)
+	header[0] = byte((bySquareLZMA.PB*5+bySquareLZMA.LP)*9 + bySquareLZMA.LC)
+	binary.LittleEndian.PutUint32(header[1:], bySquareDictCap)
+	binary.LittleEndian.PutUint64(header[5:], uint64(size))
+
+	r, err := lzma.NewReader(io.MultiReader(bytes.NewReader(header), bytes.NewReader(b[4:])))
+	if err != nil {
+		return "", errors.New("invalid pay by square compression")
+	}
+	raw, err := ioutil.ReadAll(r)
+	if err != nil || len(raw) != size || size < 4 {
+		return "", errors.New("invalid pay by square compression")
+	}
+
+	if binary.LittleEndian.Uint32(raw) != crc32.ChecksumIEEE(raw[4:]) {
+		return "", errors.New("invalid pay by square checksum")
+	}
+
+	if !utf8.Valid(raw[4:]) {
+		return "", errors.New("invalid pay by square character")
+	}
+	return string(raw[4:]), nil
+}
+
+func setBySquareIBAN(p *PayBySquareCode, iban string) error {
+	if err := ValidateIBAN(iban); err != nil {
+		return err
+	}
+	p.IBAN = iban
+	return nil
+}
+
+func setBySquareBIC(p *PayBySquareCode, bic string) error {
+	if bic != "" && len(bic) != 8 && len(bic) != 11 {
+		return errors.New("invalid BIC length")
+	}
+	p.BIC = bic
+	return nil
+}
+
+func getBySquareAmount(p *PayBySquareCode) string {
+	if p.Amount.total > 0 {
+		return p.Amount.Decimal()
+	}
+	return ""
+}
+
+func setBySquareAmount(p *PayBySquareCode, v string) error {
+	total, err := ParseDecimal(v, 2)
+	if err != nil || total > bySquareMaxAmount {
+		return errors.New("invalid amount")
+	}
+	p.Amount.total = total
+	p.Amount.decimals = 2
+	return nil
+}
+
+func getBySquareCurrency(p *PayBySquareCode) string {
+	if p.Amount.currency == "" {
+		return "EUR" // Default
+	}
+	return p.Amount.currency
+}
+
+func setBySquareCurrency(p *PayBySquareCode, v string) error {
+	if err := checkCurrency(v); err != nil {
+		return err
+	}
+	p.Amount.currency = v
+	return nil
+}
+
+func setBySquareDue(p *PayBySquareCode, v string) error {
+	if _, err := time.Parse(spaydDate, v); v != "" && err != nil {
+		return errors.New("invalid due date")
+	}
+	p.due = v
+	return nil
+}
+
+func bySquareText(name string, maxLen int, field func(p *PayBySquareCode) *string) func(p *PayBySquareCode, v string) error {
+	return func(p *PayBySquareCode, v string) error {
+		if utf8.RuneCountInString(v) > maxLen {
+			return fmt.Errorf("%s is too long", name)
+		}
+
+		if strings.ContainsAny(v, "\t\n") {
+			return fmt.Errorf("%s could not contain tabs or new lines", name)
+		}
+		*field(p) = v
+		return nil
+	}
+}
+
+func bySquareSymbol(name string, maxLen int, field func(p *PayBySquareCode) *string) func(p *PayBySquareCode, v string) error {
+	return func(p *PayBySquareCode, v string) error {
+		if len(v) > maxLen || !IsDigits(v) {
+			return fmt.Errorf("invalid %s", name)
+		}
+		*field(p) = v
+		return nil
+	}
+}
@@ -0,0 +1,148 @@
+package qr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Generated by an independent implementation (Python lzma)
+const bySquareExample = "00068000CS5C70553RQCD6JSNCI93027I20VUSE4PTTQOTR5HAOIRQQK2U1QPHK4938PUNVUNSCBMICM5T5RESJQUAHL6RA0S3NNA36EH0L83MG27LP7J95C4QSK6NTEPRT6PL3F08OJMQ15D7O49GSG1R4S5FVTKTQK0"
+
+func genBySquare(t *testing.T) *PayBySquareCode {
+	p, err := NewPayBySquarePayment("TATRSKBX", "Jan Novak", "SK3112000000198742637541")
+	assert.NoError(t, err)
+	assert.NoError(t, p.Set("amount", "25.30"))
+	assert.NoError(t, p.Set("currency", "EUR"))
+	p.DueDate(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, p.Set("variableSymbol", "123"))
+	assert.NoError(t, p.Set("constantSymbol", "0308"))
+	assert.NoError(t, p.Set("message", "Faktura 1"))
+	return p
+}
+
+func TestPayBySquareData(t *testing.T) {
+	p := genBySquare(t)
+	assert.Equal(t, "\t1\t1\t25.30\tEUR\t20240115\t123\t0308\t\t\tFaktura 1\t1\tSK3112000000198742637541\tTATRSKBX\t0\t0\tJan Novak\t\t", p.tabbed())
+	assert.NoError(t, p.Validate())
+}
+
+func TestPayBySquareRoundTrip(t *testing.T) {
+	p := genBySquare(t)
+
+	decoded, err := ParsePayBySquare(p.String())
+	assert.NoError(t, err)
+	assert.Equal(t, p, decoded)
+}
+
+func TestParsePayBySquare(t *testing.T) {
+	p, err := ParsePayBySquare(bySquareExample)
+	assert.NoError(t, err)
+	assert.Equal(t, genBySquare(t), p)
+}
+
+func TestPayBySquareHeader(t *testing.T) {
+	b, err := bySquareEncoding.DecodeString(genBySquare(t).String())
+	assert.NoError(t, err)
+	assert.Equal(t, byte(0x01), b[0], "PAY type, version 1.1.0")
+
+	// The codes of the newer versions are read too
+	b[0] = 0x02
+	p, err := ParsePayBySquare(bySquareEncoding.EncodeToString(b))
+	assert.NoError(t, err)
+	assert.Equal(t, genBySquare(t), p)
+}
+
+func TestParsePayBySquareErrors(t *testing.T) {
+	for content, msg := range map[string]string{
+		"":          "invalid pay by square encoding",
+		"W":         "invalid pay by square encoding",
+		"V006800":   "not a pay by square payment",
+		"000680000": "invalid pay by square compression",
+	} {
+		_, err := ParsePayBySquare(content)
+		assert.EqualError(t, err, msg, content)
+	}
+
+	// Broken checksum
+	data, _ := bySquareDecode(bySquareExample)
+	content, err := bySquareEncode(data)
+	assert.NoError(t, err)
+	b, _ := bySquareEncoding.DecodeString(content)
+	b[5] ^= 0xFF
+	_, err = ParsePayBySquare(bySquareEncoding.EncodeToString(b))
+	assert.Error(t, err)
+
+	// Missing required fields
+	content, err = bySquareEncode("\t1\t1\t1.00\tEUR\t\t\t\t\t\t\t1\tSK3112000000198742637541\t\t0\t0")
+	assert.NoError(t, err)
+	_, err = ParsePayBySquare(content)
+	assert.EqualError(t, err, "name is required")
+
+	content, _ = bySquareEncode("\t1\t1\t1.00\tEUR\t\t\t\t\t\t\t0")
+	_, err = ParsePayBySquare(content)
+	assert.EqualError(t, err, "no account in the code")
+}
+
+func TestParsePayBySquareExtensions(t *testing.T) {
+	// Two accounts and a standing order
+	content, err := bySquareEncode("INV1\t1\t2\t10\tEUR\t\t\t\t\tREF\tNote\t2\tSK3112000000198742637541\t\tSK3112000000198742637541\t\t1\t1\t\tM\t20251231\t0\tJan Novak\tMain street 1\tBratislava")
+	assert.NoError(t, err)
+
+	p, err := ParsePayBySquare(content)
+	assert.NoError(t, err)
+	assert.Equal(t, "INV1", p.Get("invoiceID"))
+	assert.Equal(t, "10.00", p.Get("amount"))
+	assert.Equal(t, "REF", p.Get("reference"))
+	assert.Equal(t, "Jan Novak", p.Name)
+	assert.Equal(t, "Bratislava", p.Get("address2"))
+}
+
+func TestPayBySquareFieldErrors(t *testing.T) {
+	p := &PayBySquareCode{}
+	assert.EqualError(t, p.Set("constantSymbol", "12345"), "invalid constantSymbol")
+	assert.EqualError(t, p.Set("message", "a\tb"), "message could not contain tabs or new lines")
+	assert.EqualError(t, p.Set("amount", "1.234"), "invalid amount")
+	assert.EqualError(t, p.Set("currency", "eur"), "invalid currency")
+	assert.EqualError(t, p.Set("dueDate", "2024"), "invalid due date")
+	assert.EqualError(t, p.Set("other", ""), "unknown field: other")
+	assert.Equal(t, "EUR", p.Get("currency"))
+}
+
+func TestCodePayBySquare(t *testing.T) {
+	c := genValidCode(t)
+	assert.NoError(t, c.Message("hello"))
+
+	p, err := c.PayBySquare()
+	assert.Error(t, err) // The test IBAN has a wrong checksum
+
+	c.IBAN = "HU42117730161111101800000000"
+	p, err = c.PayBySquare()
+	assert.NoError(t, err)
+	assert.Equal(t, "HUF", p.Get("currency"))
+	assert.Equal(t, "500.00", p.Get("amount"))
+	assert.Equal(t, "hello", p.Get("message"))
+	assert.Equal(t, c.Name, p.Name)
+}
+
+func TestFormatPayBySquare(t *testing.T) {
+	f, ok := LookupFormat("PayBySquare")
+	assert.True(t, ok)
+	assert.Equal(t, PayBySquareCapacityOptions, f.Capacity())
+
+	p, err := f.Decode(bySquareExample)
+	assert.NoError(t, err)
+
+	content, err := f.Encode(p)
+	assert.NoError(t, err)
+	assert.Equal(t, p.String(), content)
+
+	_, err = f.Encode(f.New())
+	assert.EqualError(t, err, "iban is required")
+	assert.EqualError(t, f.Validate(&SPAYDCode{}), "payload is not a PAY by square code")
+
+	d, _, err := Detect(bySquareExample)
+	assert.NoError(t, err)
+	assert.Equal(t, "PAYBYSQUARE", d.Name())
+}
@@ -0,0 +1,154 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// TruncatePolicy defines what happens with the message if the content does not fit
+type TruncatePolicy int
+
+const (
+	// TruncateNone leaves the content as it is
+	TruncateNone TruncatePolicy = iota
+
+	// TruncateMessage cuts the end of the message until the content fits
+	TruncateMessage
+)
+
+// CapacityOptions limits of the generated QR code
+type CapacityOptions struct {
+	Level      qrcode.RecoveryLevel // Error correction level
+	MaxVersion int                  // Highest allowed QR code version
+	MaxSize    int                  // Highest allowed content size in bytes
+	Truncate   TruncatePolicy
+}
+
+// DefaultCapacityOptions used for the generation
+var DefaultCapacityOptions = CapacityOptions{
+	Level:      qrcode.Medium,
+	MaxVersion: 13,
+	MaxSize:    qrContentMaxSize,
+	Truncate:   TruncateNone,
+}
+
+// LineSize byte size of a single payload line (without the new line)
+type LineSize struct {
+	Field string
+	Size  int
+}
+
+// Capacity report of the code content
+type Capacity struct {
+	Lines     []LineSize
+	Size      int // Full content size in bytes
+	Remaining int // Bytes which could be added to the content, negative if it is over the limits
+	Version   int // QR code version which will be generated, 0 if it is over the limits
+	Truncated int // Bytes removed from the message by the truncate policy
+}
+
+// Fits returns true if the content could be generated within the limits
+func (c Capacity) Fits() bool {
+	return c.Remaining >= 0
+}
+
+// Capacity reports the size of the content and the remaining space for the given limits
+// The truncate policy of the options is applied on the code.
+func (c *Code) Capacity(opts CapacityOptions) (*Capacity, error) {
+	truncated := 0
+	if opts.Truncate == TruncateMessage {
+		var err error
+		truncated, err = c.truncateMessage(opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	content := c.String()
+	lines := strings.Split(content, "\n")
+
+	capacity := &Capacity{
+		Lines:     make([]LineSize, 0, len(Fields)),
+		Size:      len(content),
+		Truncated: truncated,
+	}
+	for _, f := range Fields {
+		capacity.Lines = append(capacity.Lines, LineSize{Field: f.Name, Size: len(lines[f.Line])})
+	}
+
+	if v, ok := contentVersion(content, opts); ok {
+		capacity.Version = v
+		// Search the first extra size which does not fit anymore
+		capacity.Remaining = sort.Search(opts.MaxSize-len(content)+1, func(n int) bool {
+			_, ok := contentVersion(content+strings.Repeat("a", n), opts)
+			return !ok
+		}) - 1
+	} else {
+		// Search the smallest cut which would fit
+		capacity.Remaining = -sort.Search(len(content)+1, func(n int) bool {
+			_, ok := contentVersion(content[:len(content)-n], opts)
+			return ok
+		})
+	}
+
+	return capacity, nil
+}
+
+// truncateMessage cuts the message (on character boundary) until the content fits and returns the removed bytes
+func (c *Code) truncateMessage(opts CapacityOptions) (int, error) {
+	msg := c.message
+	fits := func(n int) bool {
+		cut := *c
+		cut.message = msg[:n]
+		_, ok := contentVersion(cut.String(), opts)
+		return ok
+	}
+
+	if fits(len(msg)) {
+		return 0, nil
+	}
+
+	if !fits(0) {
+		return 0, errors.New("qr content is too large even without the message")
+	}
+
+	// Search the first length which does not fit anymore
+	n := sort.Search(len(msg)+1, func(n int) bool { return !fits(n) }) - 1
+	for n > 0 && !utf8.RuneStart(msg[n]) {
+		n--
+	}
+
+	c.message = msg[:n]
+	return len(msg) - n, nil
+}
+
+// contentVersion returns the QR code version of the content and true if it is within the limits
+func contentVersion(content string, opts CapacityOptions) (int, bool) {
+	q, err := encode(content, opts)
+	if err != nil {
+		return 0, false
+	}
+	return q.VersionNumber, true
+}
+
+// encode the content with the limits checked
+func encode(content string, opts CapacityOptions) (*qrcode.QRCode, error) {
+	if len(content) > opts.MaxSize {
+		return nil, fmt.Errorf("qr content is too large: %d bytes, maximum is %d", len(content), opts.MaxSize)
+	}
+
+	q, err := qrcode.New(content, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.VersionNumber > opts.MaxVersion {
+		return nil, fmt.Errorf("qr content is too large: needs version %d, maximum is %d", q.VersionNumber, opts.MaxVersion)
+	}
+	return q, nil
+}
@@ -0,0 +1,83 @@
+package qr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapacity(t *testing.T) {
+	c, err := NewPaymentSend("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(t, err)
+
+	capacity, err := c.Capacity(DefaultCapacityOptions)
+	assert.NoError(t, err)
+	assert.True(t, capacity.Fits())
+	assert.Equal(t, len(c.String()), capacity.Size)
+	assert.Len(t, capacity.Lines, len(Fields))
+	assert.Equal(t, LineSize{Field: "iban", Size: 28}, capacity.Lines[5])
+	assert.Equal(t, LineSize{Field: "message", Size: 0}, capacity.Lines[9])
+	assert.True(t, capacity.Version > 0 && capacity.Version <= DefaultCapacityOptions.MaxVersion)
+	assert.Equal(t, 0, capacity.Truncated)
+
+	// The remaining bytes should be the exact limit
+	content := c.String()
+	_, ok := contentVersion(content+strings.Repeat("a", capacity.Remaining), DefaultCapacityOptions)
+	assert.True(t, ok)
+	_, ok = contentVersion(content+strings.Repeat("a", capacity.Remaining+1), DefaultCapacityOptions)
+	assert.False(t, ok)
+}
+
+func TestCapacityOverLimit(t *testing.T) {
+	c := genFullCode(t)
+
+	capacity, err := c.Capacity(DefaultCapacityOptions)
+	assert.NoError(t, err)
+	assert.False(t, capacity.Fits())
+	assert.Equal(t, 483, capacity.Size)
+	assert.Equal(t, 0, capacity.Version)
+	assert.Equal(t, qrContentMaxSize-483, capacity.Remaining)
+
+	// Version cap
+	opts := DefaultCapacityOptions
+	opts.MaxVersion = 1
+	c, err = NewPaymentSend("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(t, err)
+	capacity, err = c.Capacity(opts)
+	assert.NoError(t, err)
+	assert.False(t, capacity.Fits())
+}
+
+func TestCapacityTruncate(t *testing.T) {
+	c, err := NewPaymentSend("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(t, err)
+	assert.NoError(t, c.Message(strings.Repeat("á", 35)))
+
+	opts := DefaultCapacityOptions
+	opts.MaxSize = len(c.String()) - 11
+	opts.Truncate = TruncateMessage
+
+	capacity, err := c.Capacity(opts)
+	assert.NoError(t, err)
+	assert.True(t, capacity.Fits())
+	assert.Equal(t, 12, capacity.Truncated) // Cut on the character boundary
+	assert.Equal(t, strings.Repeat("á", 29), c.message)
+
+	// Nothing to cut
+	capacity, err = c.Capacity(opts)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, capacity.Truncated)
+
+	opts.MaxSize = 10
+	_, err = c.Capacity(opts)
+	assert.EqualError(t, err, "qr content is too large even without the message")
+}
+
+func TestEncodeLimits(t *testing.T) {
+	_, err := encode(strings.Repeat("a", 20), CapacityOptions{MaxSize: 10, MaxVersion: 40})
+	assert.EqualError(t, err, "qr content is too large: 20 bytes, maximum is 10")
+
+	_, err = encode(strings.Repeat("a", 20), CapacityOptions{MaxSize: 100, MaxVersion: 1, Level: DefaultCapacityOptions.Level})
+	assert.EqualError(t, err, "qr content is too large: needs version 2, maximum is 1")
+}
@@ -0,0 +1,225 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Severity of a finding
+type Severity string
+
+// Severities of the findings
+const (
+	SeverityError   Severity = "error"   // The banking apps should reject the code
+	SeverityWarning Severity = "warning" // Accepted by this package, but it could be rejected or misread
+)
+
+// Finding is a single problem of a payload
+type Finding struct {
+	Rule     string // Rule identifier, the field name and the rule, e.g. iban.checksum
+	Ref      string // Part of the MNB guideline (and the standard it refers to) which defines the rule
+	Line     int    // Line number in the payload (starting from 1), 0 for the whole payload
+	Field    string
+	Severity Severity
+	Message  string
+}
+
+// String .
+func (f Finding) String() string {
+	if f.Line == 0 {
+		return fmt.Sprintf("%s %s: %s [%s]", f.Severity, f.Rule, f.Message, f.Ref)
+	}
+	return fmt.Sprintf("line %d (%s): %s %s: %s [%s]", f.Line, f.Field, f.Severity, f.Rule, f.Message, f.Ref)
+}
+
+// mnbGuide is the MNB QR code guideline the rules come from (qr-kod-utmutato-20190712)
+const mnbGuide = "MNB QR guide 2019-07-12"
+
+// payloadRefs of the rules checking the whole payload
+var payloadRefs = map[string]string{
+	"payload.charset":     mnbGuide + ", character set",
+	"payload.line-ending": mnbGuide + ", data structure",
+	"payload.line-count":  mnbGuide + ", data structure",
+	"payload.size":        mnbGuide + ", QR code parameters",
+}
+
+// standardRefs of the field rules defined by the standards the guideline refers to
+var standardRefs = map[string]string{
+	"bic.format":    "ISO 9362",
+	"iban.format":   "ISO 13616",
+	"iban.checksum": "ISO 13616, ISO 7064 mod 97-10",
+	"purpose.code":  "ISO 20022 ExternalPurpose1Code",
+}
+
+// fieldRef is the data element of the field in the guideline, the elements are numbered by their line
+func fieldRef(f Field, rule string) string {
+	ref := fmt.Sprintf("%s, data element %d (%s)", mnbGuide, f.Line+1, f.Name)
+	if std, ok := standardRefs[rule]; ok {
+		ref += "; " + std
+	}
+	return ref
+}
+
+// Check validates a raw MNB payload line by line and returns every finding, the payload is valid without errors
+// It's stricter than Parse: the values which are fixed by the setters (e.g. an 8 character BIC) are reported too.
+func Check(content string) []Finding {
+	var findings []Finding
+	add := func(line int, field string, severity Severity, rule, ref, format string, args ...interface{}) {
+		findings = append(findings, Finding{Rule: rule, Ref: ref, Line: line, Field: field, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+	payloadProblem := func(rule, format string, args ...interface{}) {
+		add(0, "", SeverityError, rule, payloadRefs[rule], format, args...)
+	}
+
+	if !utf8.ValidString(content) {
+		payloadProblem("payload.charset", "the payload is not valid UTF-8")
+	}
+
+	if strings.Contains(content, "\r") {
+		payloadProblem("payload.line-ending", "the lines should be separated by LF only")
+		content = strings.ReplaceAll(content, "\r", "")
+	}
+
+	if len(content) > qrContentMaxSize {
+		payloadProblem("payload.size", "the payload is %d bytes, maximum is %d", len(content), qrContentMaxSize)
+	}
+
+	lines := payloadLines(content)
+
+	if len(lines) != len(Fields) {
+		payloadProblem("payload.line-count", "the payload has %d lines instead of %d", len(lines), len(Fields))
+		return findings
+	}
+
+	for _, f := range Fields {
+		value := lines[f.Line]
+		line := f.Line + 1
+		problem := func(severity Severity, rule, format string, args ...interface{}) {
+			add(line, f.Name, severity, f.Name+"."+rule, fieldRef(f, f.Name+"."+rule), format, args...)
+		}
+
+		if value == "" {
+			if f.Required {
+				problem(SeverityError, "required", "%s is required", f.Name)
+			}
+			continue
+		}
+
+		if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			problem(SeverityError, "control", "control character in the value")
+		}
+
+		if strings.TrimSpace(value) != value {
+			problem(SeverityWarning, "whitespace", "leading or trailing whitespace")
+		}
+
+		if len(value) > f.MaxLen {
+			problem(SeverityError, "length", "%d bytes, maximum is %d", len(value), f.MaxLen)
+			continue
+		}
+
+		switch f.Name {
+		case "kind":
+			if err := setKind(&Code{}, value); err != nil {
+				problem(SeverityError, "value", "should be HCT or RTP")
+			}
+		case "version":
+			if err := setVersion(&Code{}, value); err != nil {
+				problem(SeverityError, "format", "should be three digits")
+			} else if known := version("").String(); value != known {
+				problem(SeverityWarning, "unknown", "only version %s is known", known)
+			}
+		case "charset":
+			if err := setCharset(&Code{}, value); err != nil {
+				problem(SeverityError, "format", "should be one digit")
+			} else if value != "1" {
+				problem(SeverityWarning, "unknown", "only charset 1 (UTF-8) is known")
+			}
+		case "bic":
+			checkBIC(value, problem)
+		case "iban":
+			checkIBAN(value, problem)
+		case "amount":
+			checkAmount(value, problem)
+		case "valid":
+			d, err := parseDate(value)
+			if err != nil {
+				problem(SeverityError, "format", "%v, should be like 20060102150405+2", err)
+			} else if d.Expired() {
+				problem(SeverityError, "expired", "the code expired at %s", value)
+			}
+		case "purpose":
+			if err := (&Code{}).Purpose(value); err != nil {
+				problem(SeverityError, "code", "%v", err)
+			}
+		}
+	}
+	return findings
+}
+
+// Errors returns only the findings with error severity
+func Errors(findings []Finding) []Finding {
+	var errs []Finding
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			errs = append(errs, f)
+		}
+	}
+	return errs
+}
+
+func checkBIC(bic string, problem func(severity Severity, rule, format string, args ...interface{})) {
+	switch len(bic) {
+	case 11:
+	case 8:
+		problem(SeverityWarning, "length", "8 character BIC should be extended with XXX")
+	default:
+		problem(SeverityError, "length", "should be 11 characters")
+		return
+	}
+
+	for i, r := range bic {
+		letter := r >= 'A' && r <= 'Z'
+		if (i < 6 && !letter) || (i >= 6 && !letter && (r < '0' || r > '9')) {
+			problem(SeverityError, "format", "invalid character %q at position %d", r, i+1)
+			return
+		}
+	}
+
+	if bic[4:6] != "HU" {
+		problem(SeverityWarning, "country", "not a Hungarian bank")
+	}
+}
+
+// checkIBAN checks the Hungarian IBAN: the country, the length, the digits and the checksum are separate rules
+func checkIBAN(iban string, problem func(severity Severity, rule, format string, args ...interface{})) {
+	switch {
+	case !strings.HasPrefix(iban, "HU"):
+		problem(SeverityError, "country", "should be a Hungarian IBAN")
+	case len(iban) != 28:
+		problem(SeverityError, "length", "%d characters, a Hungarian IBAN has 28", len(iban))
+	case !IsDigits(iban[2:]):
+		problem(SeverityError, "format", "should contain only digits after HU")
+	case mod97(iban[4:]+iban[:4]) != 1:
+		problem(SeverityError, "checksum", "invalid IBAN checksum")
+	}
+}
+
+func checkAmount(value string, problem func(severity Severity, rule, format string, args ...interface{})) {
+	if !strings.HasPrefix(value, "HUF") {
+		problem(SeverityError, "currency", "should start with HUF")
+		return
+	}
+
+	total := value[3:]
+	switch {
+	case total == "" || !IsDigits(total):
+		problem(SeverityError, "format", "should be a whole HUF amount without separators")
+	case strings.Trim(total, "0") == "":
+		problem(SeverityWarning, "zero", "zero amount, leave it empty instead")
+	case total[0] == '0':
+		problem(SeverityWarning, "format", "leading zero")
+	}
+}
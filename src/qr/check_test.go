@@ -0,0 +1,123 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func checkLines(t *testing.T) []string {
+	c, err := NewPaymentSend("OTPVHUHB", "Test User", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	assert.NoError(t, c.HUFAmount(1500))
+	assert.NoError(t, c.ValidUntil(time.Now().Add(time.Hour)))
+	assert.NoError(t, c.Purpose("GDSV"))
+	assert.NoError(t, c.InvoiceID("INV-1"))
+	return strings.Split(c.String(), "\n")
+}
+
+func rules(findings []Finding) []string {
+	var r []string
+	for _, f := range findings {
+		r = append(r, string(f.Severity)+" "+f.Rule)
+	}
+	return r
+}
+
+func TestCheckValid(t *testing.T) {
+	lines := checkLines(t)
+	assert.Empty(t, Check(strings.Join(lines, "\n")))
+	assert.Empty(t, Check(strings.Join(lines[:len(lines)-1], "\n")))
+
+	// The output of the generator, printed with fmt.Println
+	var out strings.Builder
+	_, _ = fmt.Fprintln(&out, strings.Join(lines, "\n"))
+	assert.Empty(t, Check(out.String()))
+	assert.Empty(t, Check(out.String()+"\n\n"))
+}
+
+func TestCheckPayload(t *testing.T) {
+	lines := checkLines(t)
+
+	findings := Check(strings.Join(lines, "\r\n"))
+	assert.Equal(t, []string{"error payload.line-ending"}, rules(findings))
+	assert.Equal(t, "error payload.line-ending: the lines should be separated by LF only [MNB QR guide 2019-07-12, data structure]", findings[0].String())
+
+	assert.Equal(t, []string{"error payload.line-count"}, rules(Check(strings.Join(lines[:10], "\n"))))
+	assert.Equal(t, []string{"error payload.line-count"}, rules(Check(strings.Join(lines, "\n")+"\nextra\n")))
+
+	lines[9] = "\xff"
+	assert.Equal(t, []string{"error payload.charset"}, rules(Check(strings.Join(lines, "\n"))))
+
+	lines = checkLines(t)
+	for i := 9; i < 17; i++ {
+		lines[i] = strings.Repeat("x", 35)
+	}
+	assert.Equal(t, []string{"error payload.size"}, rules(Check(strings.Join(lines, "\n"))))
+}
+
+func TestCheckFields(t *testing.T) {
+	for _, tc := range []struct {
+		line  int
+		value string
+		rules []string
+	}{
+		{0, "", []string{"error kind.required"}},
+		{0, "XYZ", []string{"error kind.value"}},
+		{1, "1", []string{"error version.format"}},
+		{1, "002", []string{"warning version.unknown"}},
+		{2, "2", []string{"warning charset.unknown"}},
+		{2, "x", []string{"error charset.format"}},
+		{3, "OTPVHUHB", []string{"warning bic.length"}},
+		{3, "OTPVHU", []string{"error bic.length"}},
+		{3, "OTP1HUHBXXX", []string{"error bic.format"}},
+		{3, "DEUTDEFFXXX", []string{"warning bic.country"}},
+		{4, strings.Repeat("x", 71), []string{"error name.length"}},
+		{4, " Test", []string{"warning name.whitespace"}},
+		{5, "HU43117730161111101800000000", []string{"error iban.checksum"}},
+		{5, "DE89370400440532013000", []string{"error iban.country"}},
+		{5, "HU4211773016111110180000000", []string{"error iban.length"}},
+		{5, "HU42117730161111101800000A00", []string{"error iban.format"}},
+		{6, "1500", []string{"error amount.currency"}},
+		{6, "HUF1,500", []string{"error amount.format"}},
+		{6, "HUF0", []string{"warning amount.zero"}},
+		{6, "HUF01500", []string{"warning amount.format"}},
+		{7, "20200101120000+1", []string{"error valid.expired"}},
+		{7, "2020-01-01", []string{"error valid.format"}},
+		{8, "XXXX", []string{"error purpose.code"}},
+		{9, "Line\tbreak", []string{"error message.control"}},
+		{12, strings.Repeat("x", 36), []string{"error invoiceID.length"}},
+	} {
+		lines := checkLines(t)
+		lines[tc.line] = tc.value
+		assert.Equal(t, tc.rules, rules(Check(strings.Join(lines, "\n"))), tc.value)
+	}
+
+	lines := checkLines(t)
+	lines[6] = "HUF9999999999999"
+	findings := Check(strings.Join(lines, "\n"))
+	assert.Equal(t, []string{"error amount.length"}, rules(findings))
+	assert.Equal(t, "line 7 (amount): error amount.length: 16 bytes, maximum is 15 [MNB QR guide 2019-07-12, data element 7 (amount)]", findings[0].String())
+}
+
+func TestCheckRefs(t *testing.T) {
+	lines := checkLines(t)
+	lines[5] = "HU43117730161111101800000000"
+	lines[8] = "XXXX"
+	findings := Check(strings.Join(lines, "\n"))
+	assert.Equal(t, "MNB QR guide 2019-07-12, data element 6 (iban); ISO 13616, ISO 7064 mod 97-10", findings[0].Ref)
+	assert.Equal(t, "MNB QR guide 2019-07-12, data element 9 (purpose); ISO 20022 ExternalPurpose1Code", findings[1].Ref)
+
+	for rule := range payloadRefs {
+		assert.True(t, strings.HasPrefix(payloadRefs[rule], mnbGuide), rule)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	findings := []Finding{{Rule: "a", Severity: SeverityWarning}, {Rule: "b", Severity: SeverityError}}
+	assert.Equal(t, findings[1:], Errors(findings))
+	assert.Empty(t, Errors(findings[:1]))
+}
@@ -0,0 +1,99 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Loss is a field which could not be converted (or only partially) to the other format
+type Loss struct {
+	Field  string // Name of the field in the source format
+	Value  string // Original value
+	Reason string
+}
+
+// String .
+func (l Loss) String() string {
+	return fmt.Sprintf("%s (%s): %s", l.Field, l.Value, l.Reason)
+}
+
+// ToEPC converts the MNB code to a version 002 EPC code
+// The name, IBAN, BIC and purpose are copied, the message becomes the remittance text.
+// The HUF amount and the fields without an EPC counterpart are reported as losses.
+func (c Code) ToEPC() (*EPCCode, []Loss, error) {
+	e, err := NewEPCPayment(c.BIC, c.Name, c.IBAN)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := e.Set("purpose", c.purpose); c.purpose != "" && err != nil {
+		return nil, nil, err
+	}
+
+	if err := e.Text(c.message); err != nil {
+		return nil, nil, err
+	}
+
+	var losses []Loss
+	if c.Amount.total > 0 {
+		losses = append(losses, Loss{Field: "amount", Value: c.Amount.String(), Reason: "EPC supports only EUR amounts"})
+	}
+
+	if !time.Time(c.Valid).IsZero() {
+		losses = append(losses, Loss{Field: "valid", Value: c.Valid.String(), Reason: "EPC codes do not expire"})
+	}
+
+	for _, name := range []string{"shopID", "merchDevID", "invoiceID", "customerID", "credTranID", "loyaltyID", "navCheckID"} {
+		if v := c.Get(name); v != "" {
+			losses = append(losses, Loss{Field: name, Value: v, Reason: "not supported by EPC"})
+		}
+	}
+	return e, losses, nil
+}
+
+// ToMNB converts the EPC code to an MNB code of the given kind (HCT or RTP)
+// The name, IBAN, BIC and purpose are copied, the remittance text or the reference becomes the message.
+// The MNB code needs a BIC and a Hungarian IBAN, an error is returned without them.
+// The EUR amount, the beneficiary information and the cut end of a long message are reported as losses.
+func (e EPCCode) ToMNB(k kind) (*Code, []Loss, error) {
+	if !strings.HasPrefix(e.IBAN, "HU") {
+		return nil, nil, errors.New("MNB codes need a Hungarian IBAN")
+	}
+
+	c := &Code{Kind: k}
+	if err := addRecipient(c, e.BIC, e.Name, e.IBAN); err != nil {
+		return nil, nil, err
+	}
+
+	if e.purpose != "" {
+		if err := c.Purpose(e.purpose); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var losses []Loss
+	field, msg := "text", e.text
+	if e.reference != "" {
+		field, msg = "reference", e.reference
+	}
+
+	message, _ := LookupField("message")
+	if len(msg) > message.MaxLen {
+		losses = append(losses, Loss{Field: field, Value: msg, Reason: fmt.Sprintf("cut to %d bytes", message.MaxLen)})
+		msg = Truncate(msg, message.MaxLen)
+	}
+	if err := c.Message(msg); err != nil {
+		return nil, nil, err
+	}
+
+	if e.Amount.total > 0 {
+		losses = append(losses, Loss{Field: "amount", Value: e.Amount.String(), Reason: "MNB supports only HUF amounts"})
+	}
+
+	if e.information != "" {
+		losses = append(losses, Loss{Field: "information", Value: e.information, Reason: "not supported by MNB"})
+	}
+	return c, losses, nil
+}
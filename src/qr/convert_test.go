@@ -0,0 +1,87 @@
+package qr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToEPC(t *testing.T) {
+	c, err := NewPaymentSend("CIBHHUHB", "Test User", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	assert.NoError(t, c.Purpose("GDDS"))
+	assert.NoError(t, c.Message("Invoice 42"))
+
+	e, losses, err := c.ToEPC()
+	assert.NoError(t, err)
+	assert.Empty(t, losses)
+	assert.Equal(t, []string{"BCD", "002", "1", "SCT", "CIBHHUHBXXX", "Test User", "HU42117730161111101800000000", "", "GDDS", "", "Invoice 42"}, strings.Split(e.String(), "\n"))
+
+	assert.NoError(t, c.HUFAmount(500))
+	assert.NoError(t, c.NavCheckID("NAV1"))
+	_, losses, err = c.ToEPC()
+	assert.NoError(t, err)
+	assert.Equal(t, []Loss{
+		{Field: "amount", Value: "HUF500", Reason: "EPC supports only EUR amounts"},
+		{Field: "navCheckID", Value: "NAV1", Reason: "not supported by EPC"},
+	}, losses)
+	assert.Equal(t, "amount (HUF500): EPC supports only EUR amounts", losses[0].String())
+
+	// The MNB code does not check the IBAN checksum
+	c = genValidCode(t)
+	_, _, err = c.ToEPC()
+	assert.EqualError(t, err, "invalid IBAN checksum")
+}
+
+func TestToMNB(t *testing.T) {
+	e, err := ParseEPC(epcExample)
+	assert.NoError(t, err)
+
+	_, _, err = e.ToMNB(KindHCT)
+	assert.EqualError(t, err, "MNB codes need a Hungarian IBAN")
+
+	// The same length as the Hungarian IBANs
+	e, err = NewEPCPayment("BPKOPLPW", "Test User", "PL61109010140000071219812874")
+	assert.NoError(t, err)
+	_, _, err = e.ToMNB(KindHCT)
+	assert.EqualError(t, err, "MNB codes need a Hungarian IBAN")
+
+	e, err = NewEPCPayment("CIBHHUHB", "Test User", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	assert.NoError(t, e.Reference("RF18539007547034"))
+	assert.NoError(t, e.Purpose("GDDS"))
+
+	c, losses, err := e.ToMNB(KindHCT)
+	assert.NoError(t, err)
+	assert.Empty(t, losses)
+	assert.Equal(t, KindHCT, c.Kind)
+	assert.Equal(t, "CIBHHUHBXXX", c.BIC)
+	assert.Equal(t, "RF18539007547034", c.Get("message"))
+	assert.Equal(t, "GDDS", c.Get("purpose"))
+
+	e, _ = NewEPCPayment("", "Test User", "HU42117730161111101800000000")
+	_, _, err = e.ToMNB(KindHCT)
+	assert.EqualError(t, err, "invalid BIC length")
+}
+
+func TestToMNBLosses(t *testing.T) {
+	e, err := NewEPCPayment("CIBHHUHB", "Test User", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	assert.NoError(t, e.EURAmount(1250))
+	assert.NoError(t, e.Text(strings.Repeat("á", 80)))
+	assert.NoError(t, e.Information("Thanks"))
+
+	c, losses, err := e.ToMNB(KindRTP)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("á", 35), c.Get("message"))
+	assert.Equal(t, []Loss{
+		{Field: "text", Value: strings.Repeat("á", 80), Reason: "cut to 70 bytes"},
+		{Field: "amount", Value: "EUR12.50", Reason: "MNB supports only HUF amounts"},
+		{Field: "information", Value: "Thanks", Reason: "not supported by MNB"},
+	}, losses)
+
+	parsed, err := Parse(c.String())
+	assert.NoError(t, err)
+	assert.Equal(t, c.Get("message"), parsed.Get("message"))
+}
@@ -1,6 +1,12 @@
 package qr
 
-import "time"
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+const dateLayout = "20060102150405"
 
 type date time.Time
 
@@ -21,3 +27,30 @@ func (d date) Expired() bool {
 
 	return false
 }
+
+// parseDate reads back the format generated by String (timestamp, sign and the hours of the timezone)
+func parseDate(s string) (date, error) {
+	if len(s) < len(dateLayout)+2 || len(s) > len(dateLayout)+3 {
+		return date{}, errors.New("invalid date length")
+	}
+
+	sign := 1
+	switch s[len(dateLayout)] {
+	case '+':
+	case '-':
+		sign = -1
+	default:
+		return date{}, errors.New("invalid date timezone")
+	}
+
+	hours, err := strconv.Atoi(s[len(dateLayout)+1:])
+	if err != nil || hours > 14 {
+		return date{}, errors.New("invalid date timezone")
+	}
+
+	t, err := time.ParseInLocation(dateLayout, s[:len(dateLayout)], time.FixedZone("", sign*hours*60*60))
+	if err != nil {
+		return date{}, errors.New("invalid date")
+	}
+	return date(t), nil
+}
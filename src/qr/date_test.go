@@ -26,3 +26,30 @@ func TestDateToString(t *testing.T) {
 		assert.Equal(t, tt.expectedOutput, tt.input.String())
 	}
 }
+
+func TestParseDate(t *testing.T) {
+	testTable := []struct {
+		input       string
+		expectedErr string
+	}{
+		{"20200518101123+0", ""},
+		{"20200518101123+2", ""},
+		{"20200518101123-1", ""},
+		{"20200518101123+11", ""},
+		{"20200518101123", "invalid date length"},
+		{"20200518101123+111", "invalid date length"},
+		{"20200518101123*1", "invalid date timezone"},
+		{"20200518101123+x", "invalid date timezone"},
+		{"20201318101123+1", "invalid date"},
+	}
+
+	for _, tt := range testTable {
+		d, err := parseDate(tt.input)
+		if tt.expectedErr != "" {
+			assert.EqualError(t, err, tt.expectedErr, tt.input)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tt.input, d.String())
+	}
+}
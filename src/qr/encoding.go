@@ -0,0 +1,52 @@
+package qr
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// errNoValidity is returned for the codes without validity, String would add a new default to them every time
+var errNoValidity = errors.New("validity is not set")
+
+// MarshalText returns the payload, the same as String
+// The validity should be set, the stored code should not get a deadline nobody set.
+func (c Code) MarshalText() ([]byte, error) {
+	if time.Time(c.Valid).IsZero() {
+		return nil, errNoValidity
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText parses the payload
+func (c *Code) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*c = *parsed
+	return nil
+}
+
+// Value stores the code as its payload in the database, the validity should be set like for MarshalText
+func (c Code) Value() (driver.Value, error) {
+	text, err := c.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// Scan loads the code from a payload stored in the database
+func (c *Code) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return c.UnmarshalText([]byte(v))
+	case []byte:
+		return c.UnmarshalText(v)
+	case nil:
+		return fmt.Errorf("cannot scan NULL into qr.Code")
+	}
+	return fmt.Errorf("cannot scan %T into qr.Code", src)
+}
@@ -0,0 +1,81 @@
+package qr
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.TextMarshaler   = Code{}
+	_ encoding.TextUnmarshaler = &Code{}
+	_ driver.Valuer            = Code{}
+	_ sql.Scanner              = &Code{}
+)
+
+func TestTextRoundTrip(t *testing.T) {
+	c := genFullCode(t)
+
+	text, err := c.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, c.String(), string(text))
+
+	var parsed Code
+	assert.NoError(t, parsed.UnmarshalText(text))
+	assert.Equal(t, c.String(), parsed.String())
+
+	assert.EqualError(t, parsed.UnmarshalText([]byte("HCT")), "invalid line count: 1")
+
+	// Marshalled twice the same, the parsed validity is kept
+	again, err := parsed.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, text, again)
+
+	// No default validity is stored
+	c, err = NewPaymentSend("OTPVHUHB", "Test User", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	_, err = c.MarshalText()
+	assert.EqualError(t, err, "validity is not set")
+	_, err = c.Value()
+	assert.EqualError(t, err, "validity is not set")
+	_, err = json.Marshal(c)
+	assert.Error(t, err)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	c := genFullCode(t)
+
+	b, err := json.Marshal(struct {
+		Code Code `json:"code"`
+	}{*c})
+	assert.NoError(t, err)
+
+	var parsed struct {
+		Code *Code `json:"code"`
+	}
+	assert.NoError(t, json.Unmarshal(b, &parsed))
+	assert.Equal(t, c.String(), parsed.Code.String())
+}
+
+func TestValueScan(t *testing.T) {
+	c := genFullCode(t)
+
+	v, err := c.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, c.String(), v)
+
+	var scanned Code
+	assert.NoError(t, scanned.Scan(v))
+	assert.Equal(t, c.String(), scanned.String())
+
+	scanned = Code{}
+	assert.NoError(t, scanned.Scan([]byte(c.String())))
+	assert.Equal(t, c.String(), scanned.String())
+
+	assert.EqualError(t, scanned.Scan(nil), "cannot scan NULL into qr.Code")
+	assert.EqualError(t, scanned.Scan(5), "cannot scan int into qr.Code")
+}
@@ -0,0 +1,481 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	epcServiceTag     = "BCD"
+	epcIdentification = "SCT"
+	epcContentMaxSize = 331
+	epcMaxAmount      = 99999999999 // 999999999.99 EUR in cents
+)
+
+var (
+	// KindEPC for SEPA credit transfer (EPC069-12), the payload itself starts with the BCD service tag
+	KindEPC kind = "EPC"
+
+	// EPCCapacityOptions required by the EPC guideline
+	EPCCapacityOptions = CapacityOptions{
+		Level:      qrcode.Medium,
+		MaxVersion: 13,
+		MaxSize:    epcContentMaxSize,
+		Truncate:   TruncateNone,
+	}
+)
+
+// EPCCode is a SEPA credit transfer QR code, also known as GiroCode
+// Standard: https://www.europeanpaymentscouncil.eu/sites/default/files/kb/file/2018-05/EPC069-12%20v2.1%20Quick%20Response%20Code%20-%20Guidelines%20to%20Enable%20the%20Data%20Capture%20for%20the%20Initiation%20of%20a%20SCT.pdf
+type EPCCode struct {
+	Version     string // Required, 001 or 002
+	Charset     int    // Required, 1 (UTF-8) by default
+	BIC         string // Required in version 001
+	Name        string // Required
+	IBAN        string // Required
+	Amount      amount
+	purpose     string
+	reference   string // Structured creditor reference
+	text        string // Unstructured remittance information
+	information string // Beneficiary to originator information
+}
+
+type epcField struct {
+	Field
+	get func(e *EPCCode) string
+	set func(e *EPCCode, value string) error
+}
+
+var epcFields = []epcField{
+	{
+		Field: Field{Line: 0, Name: "serviceTag", Type: "string", MaxLen: 3, Required: true, Usage: "always `BCD`"},
+		get:   func(e *EPCCode) string { return epcServiceTag },
+		set:   constant("serviceTag", epcServiceTag),
+	},
+	{
+		Field: Field{Line: 1, Name: "version", Type: "string", MaxLen: 3, Required: true, Usage: "`001` or `002` (default), the BIC is optional in `002`"},
+		get:   getEPCVersion,
+		set:   setEPCVersion,
+	},
+	{
+		Field: Field{Line: 2, Name: "charset", Type: "int", MaxLen: 1, Required: true, Usage: "character set `1`-`8`, `1` (UTF-8) by default"},
+		get:   getEPCCharset,
+		set:   setEPCCharset,
+	},
+	{
+		Field: Field{Line: 3, Name: "identification", Type: "string", MaxLen: 3, Required: true, Usage: "always `SCT`"},
+		get:   func(e *EPCCode) string { return epcIdentification },
+		set:   constant("identification", epcIdentification),
+	},
+	{
+		Field: Field{Line: 4, Name: "bic", Type: "string", MaxLen: 11, Usage: "`8` or `11` character, required in version `001`"},
+		get:   func(e *EPCCode) string { return e.BIC },
+		set:   setEPCBIC,
+	},
+	{
+		Field: Field{Line: 5, Name: "name", Type: "string", MaxLen: 70, Required: true, Usage: "beneficiary name"},
+		get:   func(e *EPCCode) string { return e.Name },
+		set:   setEPCName,
+	},
+	{
+		Field: Field{Line: 6, Name: "iban", Type: "string", MaxLen: 34, Required: true, Usage: "beneficiary IBAN"},
+		get:   func(e *EPCCode) string { return e.IBAN },
+		set:   setEPCIBAN,
+	},
+	{
+		Field: Field{Line: 7, Name: "amount", Type: "string", MaxLen: 15, Usage: "amount in EUR, `0.01` - `999999999.99`"},
+		get:   getEPCAmount,
+		set:   setEPCAmount,
+	},
+	{
+		Field: Field{Line: 8, Name: "purpose", Type: "string", MaxLen: 4, Usage: "from a fixed set, check the `PurposeCodes` catalogue or `mnb-qr-gen purposes`"},
+		get:   func(e *EPCCode) string { return e.purpose },
+		set:   func(e *EPCCode, v string) error { return e.Purpose(v) },
+	},
+	{
+		Field: Field{Line: 9, Name: "reference", Type: "string", MaxLen: 35, Usage: "structured creditor reference, can't be used with `text`"},
+		get:   func(e *EPCCode) string { return e.reference },
+		set:   func(e *EPCCode, v string) error { return e.Reference(v) },
+	},
+	{
+		Field: Field{Line: 10, Name: "text", Type: "string", MaxLen: 140, Usage: "unstructured remittance information, can't be used with `reference`"},
+		get:   func(e *EPCCode) string { return e.text },
+		set:   func(e *EPCCode, v string) error { return e.Text(v) },
+	},
+	{
+		Field: Field{Line: 11, Name: "information", Type: "string", MaxLen: 70, Usage: "beneficiary to originator information"},
+		get:   func(e *EPCCode) string { return e.information },
+		set:   func(e *EPCCode, v string) error { return e.Information(v) },
+	},
+}
+
+// EPCFields of the EPC code in payload order
+func EPCFields() []Field {
+	fields := make([]Field, 0, len(epcFields))
+	for _, f := range epcFields {
+		set := f.set
+		field := f.Field
+		field.check = func(v string) error { return set(&EPCCode{}, v) }
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// NewEPCPayment creates a version 002 SEPA credit transfer code
+func NewEPCPayment(bic string, name string, iban string) (*EPCCode, error) {
+	e := &EPCCode{
+		Version: "002",
+	}
+
+	for _, f := range []struct{ name, value string }{{"bic", bic}, {"name", name}, {"iban", iban}} {
+		if err := e.Set(f.name, f.value); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// ParseEPC reads an EPC payload, LF and CRLF separators are both accepted
+// Payloads in a single byte character set are converted to UTF-8.
+func ParseEPC(content string) (*EPCCode, error) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) < 7 || len(lines) > len(epcFields) {
+		return nil, fmt.Errorf("invalid line count: %d", len(lines))
+	}
+
+	e := &EPCCode{}
+	if err := setEPCCharset(e, lines[2]); err != nil {
+		return nil, err
+	}
+	if !utf8.ValidString(content) && e.Charset != 1 {
+		for i := range lines {
+			lines[i] = decodeLatin1(lines[i])
+		}
+	}
+
+	for _, f := range epcFields {
+		value := ""
+		if f.Line < len(lines) {
+			value = lines[f.Line]
+		}
+
+		if value == "" {
+			if f.Required {
+				return nil, fmt.Errorf("%s is required", f.Name)
+			}
+			continue
+		}
+
+		if err := f.set(e, value); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Set a field by its name
+func (e *EPCCode) Set(name string, value string) error {
+	for _, f := range epcFields {
+		if f.Name == name {
+			return f.set(e, value)
+		}
+	}
+	return fmt.Errorf("unknown field: %s", name)
+}
+
+// Get a field value by its name, it returns the same value as the payload would contain
+func (e EPCCode) Get(name string) string {
+	for _, f := range epcFields {
+		if f.Name == name {
+			return f.get(&e)
+		}
+	}
+	return ""
+}
+
+// EURAmount for the transaction in cents
+func (e *EPCCode) EURAmount(cents int) error {
+	if cents < 1 {
+		return errors.New("amount should be at least 0.01")
+	}
+
+	if cents > epcMaxAmount {
+		return errors.New("amount could not be higher than 999999999.99")
+	}
+
+	e.Amount = amount{
+		currency: "EUR",
+		total:    cents,
+		decimals: 2,
+	}
+	return nil
+}
+
+// Purpose for the transaction, it should be a code from the PurposeCodes catalogue
+func (e *EPCCode) Purpose(purpose string) error {
+	p, ok := LookupPurpose(purpose)
+	if !ok {
+		return errors.New("invalid purpose code")
+	}
+	e.purpose = p.Code
+	return nil
+}
+
+// Reference sets the structured creditor reference
+func (e *EPCCode) Reference(ref string) error {
+	if utf8.RuneCountInString(ref) > 35 {
+		return errors.New("reference is too long")
+	}
+
+	if ref != "" && e.text != "" {
+		return errors.New("reference and text could not be used together")
+	}
+	e.reference = ref
+	return nil
+}
+
+// Text sets the unstructured remittance information
+func (e *EPCCode) Text(text string) error {
+	if utf8.RuneCountInString(text) > 140 {
+		return errors.New("text is too long")
+	}
+
+	if text != "" && e.reference != "" {
+		return errors.New("reference and text could not be used together")
+	}
+	e.text = text
+	return nil
+}
+
+// Information sets the beneficiary to originator information
+func (e *EPCCode) Information(info string) error {
+	if utf8.RuneCountInString(info) > 70 {
+		return errors.New("information is too long")
+	}
+	e.information = info
+	return nil
+}
+
+// Validate the rules which are depending on more fields
+func (e EPCCode) Validate() error {
+	if e.Name == "" {
+		return errors.New("name is required")
+	}
+
+	if e.IBAN == "" {
+		return errors.New("iban is required")
+	}
+
+	if e.BIC == "" && e.Get("version") == "001" {
+		return errors.New("bic is required in version 001")
+	}
+
+	content, err := e.encoded()
+	if err != nil {
+		return err
+	}
+
+	if len(content) > epcContentMaxSize {
+		return fmt.Errorf("epc content is too large: %d bytes, maximum is %d", len(content), epcContentMaxSize)
+	}
+	return nil
+}
+
+// String returns the payload, the empty lines at the end are omitted
+func (e EPCCode) String() string {
+	lines := make([]string, 0, len(epcFields))
+	for _, f := range epcFields {
+		lines = append(lines, f.get(&e))
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// WriteTo writes the payload, the same as String
+func (e EPCCode) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, e.String())
+	return int64(n), err
+}
+
+// GeneratePNG returns the code as a PNG image
+func (e EPCCode) GeneratePNG(size int) ([]byte, error) {
+	var buf bytes.Buffer
+	err := e.WritePNG(&buf, RenderOptions{Size: size})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WritePNG renders the code as a PNG image into the writer
+func (e EPCCode) WritePNG(w io.Writer, opts RenderOptions) error {
+	q, err := e.qrCode(opts)
+	if err != nil {
+		return err
+	}
+	return writePNG(w, q, opts, e.readBack())
+}
+
+// WriteSVG renders the code as an SVG image into the writer
+func (e EPCCode) WriteSVG(w io.Writer, opts RenderOptions) error {
+	q, err := e.qrCode(opts)
+	if err != nil {
+		return err
+	}
+	return writeVerifiedSVG(w, q, opts, e.readBack())
+}
+
+func (e EPCCode) qrCode(opts RenderOptions) (*qrcode.QRCode, error) {
+	if err := e.Validate(); err != nil {
+		return nil, err
+	}
+
+	content, err := e.encoded()
+	if err != nil {
+		return nil, err
+	}
+	return encode(content, opts.capacity(EPCCapacityOptions))
+}
+
+// encoded returns the payload in the selected character set
+// Only UTF-8 and ISO 8859-1 are converted, the other sets are accepted with ASCII content.
+func (e EPCCode) encoded() (string, error) {
+	content := e.String()
+	switch e.Get("charset") {
+	case "1":
+		return content, nil
+	case "2":
+		return encodeLatin1(content)
+	}
+
+	for _, r := range content {
+		if r >= utf8.RuneSelf {
+			return "", fmt.Errorf("charset %s supports only ASCII content", e.Get("charset"))
+		}
+	}
+	return content, nil
+}
+
+// readBack is the UTF-8 text and the charset of the encoded payload
+func (e EPCCode) readBack() readBack {
+	if e.Get("charset") == "2" {
+		return readBack{text: e.String(), charset: charsetLatin1}
+	}
+	return readBack{text: e.String(), charset: charsetUTF8}
+}
+
+func encodeLatin1(s string) (string, error) {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		if r > 0xFF {
+			return "", fmt.Errorf("character %q is not in ISO 8859-1", r)
+		}
+		b = append(b, byte(r))
+	}
+	return string(b), nil
+}
+
+func decodeLatin1(s string) string {
+	runes := make([]rune, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		runes = append(runes, rune(s[i]))
+	}
+	return string(runes)
+}
+
+func constant(name, expected string) func(e *EPCCode, v string) error {
+	return func(e *EPCCode, v string) error {
+		if v != expected {
+			return fmt.Errorf("invalid %s", name)
+		}
+		return nil
+	}
+}
+
+func getEPCVersion(e *EPCCode) string {
+	if e.Version == "" {
+		return "002" // Default
+	}
+	return e.Version
+}
+
+func setEPCVersion(e *EPCCode, v string) error {
+	if v != "001" && v != "002" {
+		return errors.New("invalid version")
+	}
+	e.Version = v
+	return nil
+}
+
+func getEPCCharset(e *EPCCode) string {
+	if e.Charset == 0 {
+		return "1" // UTF-8 by default
+	}
+	return strconv.Itoa(e.Charset)
+}
+
+func setEPCCharset(e *EPCCode, v string) error {
+	charset, err := strconv.Atoi(v)
+	if err != nil || len(v) != 1 || charset < 1 || charset > 8 {
+		return errors.New("invalid charset")
+	}
+	e.Charset = charset
+	return nil
+}
+
+func setEPCBIC(e *EPCCode, bic string) error {
+	if bic != "" && len(bic) != 8 && len(bic) != 11 {
+		return errors.New("invalid BIC length")
+	}
+	e.BIC = bic
+	return nil
+}
+
+func setEPCName(e *EPCCode, name string) error {
+	if utf8.RuneCountInString(name) > 70 {
+		return errors.New("name should not be longer than 70")
+	}
+	e.Name = name
+	return nil
+}
+
+func setEPCIBAN(e *EPCCode, iban string) error {
+	if err := ValidateIBAN(iban); err != nil {
+		return err
+	}
+	e.IBAN = iban
+	return nil
+}
+
+func getEPCAmount(e *EPCCode) string {
+	if e.Amount.total > 0 {
+		return e.Amount.String()
+	}
+	return ""
+}
+
+func setEPCAmount(e *EPCCode, v string) error {
+	cents, err := ParseDecimal(strings.TrimPrefix(v, "EUR"), 2)
+	if err != nil {
+		return errors.New("invalid amount")
+	}
+	return e.EURAmount(cents)
+}
@@ -0,0 +1,151 @@
+package qr
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const epcExample = "BCD\n002\n1\nSCT\nBFSWDE33BER\nWikimedia Foerdergesellschaft\nDE33100205000001194700\nEUR123.45\n\n\nSpende fuer Wikipedia"
+
+func TestEPCFormat(t *testing.T) {
+	e, err := NewEPCPayment("BFSWDE33BER", "Wikimedia Foerdergesellschaft", "DE33100205000001194700")
+	assert.NoError(t, err)
+	assert.NoError(t, e.EURAmount(12345))
+	assert.NoError(t, e.Text("Spende fuer Wikipedia"))
+
+	assert.Equal(t, epcExample, e.String())
+	assert.NoError(t, e.Validate())
+}
+
+func TestEPCFullFormat(t *testing.T) {
+	e, err := NewEPCPayment("", "Test User", "DE89370400440532013000")
+	assert.NoError(t, err)
+	assert.NoError(t, e.Set("amount", "0.5"))
+	assert.NoError(t, e.Purpose("gdds"))
+	assert.NoError(t, e.Reference("RF18539007547034"))
+	assert.NoError(t, e.Information("Thanks"))
+
+	lines := strings.Split(e.String(), "\n")
+	assert.Equal(t, []string{"BCD", "002", "1", "SCT", "", "Test User", "DE89370400440532013000", "EUR0.50", "GDDS", "RF18539007547034", "", "Thanks"}, lines)
+}
+
+func TestParseEPC(t *testing.T) {
+	e, err := ParseEPC(epcExample)
+	assert.NoError(t, err)
+	assert.Equal(t, "002", e.Version)
+	assert.Equal(t, "BFSWDE33BER", e.BIC)
+	assert.Equal(t, "Wikimedia Foerdergesellschaft", e.Name)
+	assert.Equal(t, "DE33100205000001194700", e.IBAN)
+	assert.Equal(t, "EUR", e.Amount.Currency())
+	assert.Equal(t, "123.45", e.Amount.Decimal())
+	assert.Equal(t, "Spende fuer Wikipedia", e.text)
+	assert.Equal(t, epcExample, e.String())
+
+	// CRLF and a trailing new line
+	e, err = ParseEPC(strings.ReplaceAll(epcExample, "\n", "\r\n") + "\r\n")
+	assert.NoError(t, err)
+	assert.Equal(t, epcExample, e.String())
+
+	// ISO 8859-1 payload
+	e, err = ParseEPC("BCD\n001\n2\nSCT\nCOBADEFFXXX\nJ\xfcrgen\nDE89370400440532013000")
+	assert.NoError(t, err)
+	assert.Equal(t, "Jürgen", e.Name)
+}
+
+func TestParseEPCErrors(t *testing.T) {
+	testTable := []struct {
+		input       string
+		expectedErr string
+	}{
+		{"BCD\n002\n1\nSCT", "invalid line count: 4"},
+		{epcExample + "\na\nb", "invalid line count: 13"},
+		{strings.Replace(epcExample, "BCD", "BCE", 1), "invalid serviceTag"},
+		{strings.Replace(epcExample, "SCT", "INST", 1), "invalid identification"},
+		{strings.Replace(epcExample, "002", "003", 1), "invalid version"},
+		{strings.Replace(epcExample, "\n1\n", "\n9\n", 1), "invalid charset"},
+		{strings.Replace(epcExample, "DE33100205000001194700", "DE33100205000001194701", 1), "invalid IBAN checksum"},
+		{strings.Replace(epcExample, "Wikimedia Foerdergesellschaft", "", 1), "name is required"},
+		{strings.Replace(epcExample, "EUR123.45", "EUR123.456", 1), "invalid amount"},
+		{strings.Replace(epcExample, "\n\n\n", "\n\nRF18539007547034\n", 1), "reference and text could not be used together"},
+		{"BCD\n001\n1\nSCT\n\nTest User\nDE89370400440532013000", "bic is required in version 001"},
+	}
+
+	for _, tt := range testTable {
+		_, err := ParseEPC(tt.input)
+		assert.EqualError(t, err, tt.expectedErr, tt.input)
+	}
+}
+
+func TestEPCSetErrors(t *testing.T) {
+	e := &EPCCode{}
+
+	assert.EqualError(t, e.Set("something", "x"), "unknown field: something")
+	assert.EqualError(t, e.EURAmount(0), "amount should be at least 0.01")
+	assert.EqualError(t, e.EURAmount(100000000000), "amount could not be higher than 999999999.99")
+	assert.EqualError(t, e.Purpose("XXXX"), "invalid purpose code")
+	assert.EqualError(t, e.Reference(strings.Repeat("a", 36)), "reference is too long")
+	assert.EqualError(t, e.Text(strings.Repeat("a", 141)), "text is too long")
+	assert.EqualError(t, e.Information(strings.Repeat("a", 71)), "information is too long")
+	assert.EqualError(t, e.Set("bic", "abc"), "invalid BIC length")
+	assert.EqualError(t, e.Set("name", strings.Repeat("á", 71)), "name should not be longer than 70")
+	assert.NoError(t, e.Set("name", strings.Repeat("á", 70)))
+}
+
+func TestEPCFields(t *testing.T) {
+	fields := EPCFields()
+	assert.Len(t, fields, 12)
+	for i, f := range fields {
+		assert.Equal(t, i, f.Line)
+	}
+
+	assert.NoError(t, fields[7].Validate("12.30"))
+	assert.EqualError(t, fields[7].Validate("abc"), "invalid amount")
+}
+
+func TestEPCGeneratePNG(t *testing.T) {
+	e, err := ParseEPC(epcExample)
+	assert.NoError(t, err)
+
+	png, err := e.GeneratePNG(256)
+	assert.NoError(t, err)
+	assert.True(t, len(png) > 100)
+
+	var sb strings.Builder
+	assert.NoError(t, e.WriteSVG(&sb, RenderOptions{Size: 256}))
+	assert.True(t, strings.HasPrefix(sb.String(), "<svg"))
+
+	n, err := e.WriteTo(&sb)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(epcExample)), n)
+
+	// Too large content
+	assert.NoError(t, e.Text(strings.Repeat("a", 140)))
+	assert.NoError(t, e.Information(strings.Repeat("á", 70)))
+	assert.NoError(t, e.Set("name", strings.Repeat("á", 70)))
+	_, err = e.GeneratePNG(256)
+	assert.EqualError(t, err, "epc content is too large: 483 bytes, maximum is 331")
+
+	// Charsets
+	e, err = ParseEPC(epcExample)
+	assert.NoError(t, err)
+	e.Charset = 3
+	_, err = e.GeneratePNG(256)
+	assert.NoError(t, err)
+
+	assert.NoError(t, e.Set("name", "Jürgen"))
+	_, err = e.GeneratePNG(256)
+	assert.EqualError(t, err, "charset 3 supports only ASCII content")
+
+	e.Charset = 2
+	_, err = e.GeneratePNG(256)
+	assert.NoError(t, err)
+	content, err := e.encoded()
+	assert.NoError(t, err)
+	assert.Contains(t, content, "J\xfcrgen")
+
+	assert.NoError(t, e.Set("name", "Győző"))
+	_, err = e.GeneratePNG(256)
+	assert.EqualError(t, err, `character 'ő' is not in ISO 8859-1`)
+}
@@ -0,0 +1,260 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field describes one line of the QR code payload
+// This is the single source of the layout, the serialisation, parsing, the server input and the CLI flags are all built from it.
+type Field struct {
+	Line     int    // Line index in the payload
+	Name     string // Name used in JSON, CLI flags and Set
+	Type     string // Input type for the docs (string or int)
+	MaxLen   int    // Maximum length of the value
+	Required bool   // Required by the standard
+	Usage    string // Short description for the docs and the CLI
+
+	get   func(c *Code) string
+	set   func(c *Code, value string) error
+	check func(value string) error // Used by the fields of the other formats
+}
+
+// Fields of the MNB QR code in payload order
+var Fields = []Field{
+	{
+		Line: 0, Name: "kind", Type: "string", MaxLen: 3, Required: true,
+		Usage: "QR code type, `RTP` or `HCT`",
+		get:   func(c *Code) string { return c.Kind.String() },
+		set:   setKind,
+	},
+	{
+		Line: 1, Name: "version", Type: "string", MaxLen: 3, Required: true,
+		Usage: "version of the standard, `001` by default",
+		get:   func(c *Code) string { return c.Version.String() },
+		set:   setVersion,
+	},
+	{
+		Line: 2, Name: "charset", Type: "int", MaxLen: 1, Required: true,
+		Usage: "character set, `1` by default",
+		get:   getCharset,
+		set:   setCharset,
+	},
+	{
+		Line: 3, Name: "bic", Type: "string", MaxLen: 11, Required: true,
+		Usage: "`8` or `11` character, the `8` char long will get a `XXX` postfix",
+		get:   func(c *Code) string { return c.BIC },
+		set:   setBIC,
+	},
+	{
+		Line: 4, Name: "name", Type: "string", MaxLen: 70, Required: true,
+		Usage: "recipient or sender name",
+		get:   func(c *Code) string { return c.Name },
+		set:   setName,
+	},
+	{
+		Line: 5, Name: "iban", Type: "string", MaxLen: 28, Required: true,
+		Usage: "IBAN number",
+		get:   func(c *Code) string { return c.IBAN },
+		set:   setIBAN,
+	},
+	{
+		Line: 6, Name: "amount", Type: "int", MaxLen: 15,
+		Usage: "amount in HUF",
+		get:   getAmount,
+		set:   setAmount,
+	},
+	{
+		Line: 7, Name: "valid", Type: "string", MaxLen: 17, Required: true,
+		Usage: "validity timestamp in `20060102150405+2` format, one hour from now by default",
+		get:   getValid,
+		set:   setValid,
+	},
+	{
+		Line: 8, Name: "purpose", Type: "string", MaxLen: 4,
+		Usage: "from a fixed set, check the `PurposeCodes` catalogue or `mnb-qr-gen purposes`",
+		get:   func(c *Code) string { return c.purpose },
+		set:   func(c *Code, v string) error { return c.Purpose(v) },
+	},
+	textField(9, "message", 70, "message added to the code", func(c *Code) *string { return &c.message }),
+	textField(10, "shopID", 35, "shop identifier", func(c *Code) *string { return &c.shopID }),
+	textField(11, "merchDevID", 35, "merchant device identifier", func(c *Code) *string { return &c.merchDevID }),
+	textField(12, "invoiceID", 35, "invoice identifier", func(c *Code) *string { return &c.invoiceID }),
+	textField(13, "customerID", 35, "customer identifier", func(c *Code) *string { return &c.customerID }),
+	textField(14, "credTranID", 35, "creditor transaction identifier", func(c *Code) *string { return &c.credTranID }),
+	textField(15, "loyaltyID", 35, "loyalty identifier", func(c *Code) *string { return &c.loyaltyID }),
+	textField(16, "navCheckID", 35, "NAV check identifier", func(c *Code) *string { return &c.navCheckID }),
+}
+
+// LookupField returns the field with the given name
+func LookupField(name string) (Field, bool) {
+	for _, f := range Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// Validate checks the value without setting it on a code
+func (f Field) Validate(value string) error {
+	if f.check != nil {
+		return f.check(value)
+	}
+	return f.set(&Code{}, value)
+}
+
+// Set a field by its name
+func (c *Code) Set(name string, value string) error {
+	f, ok := LookupField(name)
+	if !ok {
+		return fmt.Errorf("unknown field: %s", name)
+	}
+	return f.set(c, value)
+}
+
+// Get a field value by its name, it returns the same value as the payload would contain
+func (c Code) Get(name string) string {
+	f, ok := LookupField(name)
+	if !ok {
+		return ""
+	}
+	return f.get(&c)
+}
+
+// WriteFieldDocs writes the markdown documentation of the fields (Fields or EPCFields)
+func WriteFieldDocs(w io.Writer, fields []Field) error {
+	for _, required := range []bool{true, false} {
+		if required {
+			_, _ = fmt.Fprintln(w, "Required:")
+		} else {
+			_, _ = fmt.Fprintln(w, "\nOptional:")
+		}
+
+		for _, f := range fields {
+			if f.Required != required {
+				continue
+			}
+			_, err := fmt.Fprintf(w, "- `%s` - %s (%d chars max, %s)\n", f.Name, f.Type, f.MaxLen, f.Usage)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func textField(line int, name string, maxLen int, usage string, value func(c *Code) *string) Field {
+	return Field{
+		Line: line, Name: name, Type: "string", MaxLen: maxLen, Usage: usage,
+		get: func(c *Code) string { return *value(c) },
+		set: func(c *Code, v string) error {
+			if len(v) > maxLen {
+				return fmt.Errorf("%s is too long", name)
+			}
+			*value(c) = v
+			return nil
+		},
+	}
+}
+
+func setKind(c *Code, v string) error {
+	switch kind(v) {
+	case KindHCT, KindRTP:
+		c.Kind = kind(v)
+		return nil
+	}
+	return errors.New("invalid kind")
+}
+
+func setVersion(c *Code, v string) error {
+	if len(v) != 3 {
+		return errors.New("invalid version")
+	}
+	if _, err := strconv.Atoi(v); err != nil {
+		return errors.New("invalid version")
+	}
+	c.Version = version(v)
+	return nil
+}
+
+func getCharset(c *Code) string {
+	if c.Charset == 0 {
+		return "1" // Set default to 1
+	}
+	return strconv.Itoa(c.Charset)
+}
+
+func setCharset(c *Code, v string) error {
+	charset, err := strconv.Atoi(v)
+	if err != nil || len(v) != 1 || charset < 1 {
+		return errors.New("invalid charset")
+	}
+	c.Charset = charset
+	return nil
+}
+
+func setBIC(c *Code, bic string) error {
+	if len(bic) == 8 {
+		bic = bic + "XXX" // For SEPA payment the 8 char long SWIFT should be extended with XXX to 11 chars
+	}
+
+	if len(bic) != 11 {
+		return errors.New("invalid BIC length")
+	}
+	c.BIC = bic
+	return nil
+}
+
+func setName(c *Code, name string) error {
+	if len(name) > 70 {
+		return errors.New("name should not be longer than 70")
+	}
+	c.Name = name
+	return nil
+}
+
+func setIBAN(c *Code, iban string) error {
+	if len(iban) != 28 {
+		return errors.New("invalid IBAN length")
+	}
+	c.IBAN = iban
+	return nil
+}
+
+func getAmount(c *Code) string {
+	// Optional amount
+	if c.Amount.total > 0 {
+		return c.Amount.String()
+	}
+	return ""
+}
+
+func setAmount(c *Code, v string) error {
+	total, err := strconv.Atoi(strings.TrimPrefix(v, "HUF"))
+	if err != nil {
+		return errors.New("invalid amount")
+	}
+	return c.HUFAmount(total)
+}
+
+func getValid(c *Code) string {
+	if time.Time(c.Valid).IsZero() {
+		// Add a default time with one hour expire
+		return date(time.Now().Add(time.Hour)).String()
+	}
+	return c.Valid.String()
+}
+
+func setValid(c *Code, v string) error {
+	d, err := parseDate(v)
+	if err != nil {
+		return err
+	}
+	c.Valid = d
+	return nil
+}
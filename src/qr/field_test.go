@@ -0,0 +1,89 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldsOrder(t *testing.T) {
+	for i, f := range Fields {
+		assert.Equal(t, i, f.Line, f.Name)
+		assert.NotEmpty(t, f.Usage, f.Name)
+		assert.True(t, f.MaxLen > 0, f.Name)
+	}
+}
+
+func TestLookupField(t *testing.T) {
+	f, ok := LookupField("loyaltyID")
+	assert.True(t, ok)
+	assert.Equal(t, 15, f.Line)
+	assert.Equal(t, 35, f.MaxLen)
+
+	_, ok = LookupField("something")
+	assert.False(t, ok)
+}
+
+func TestFieldValidate(t *testing.T) {
+	testTable := []struct {
+		field       string
+		value       string
+		expectedErr string
+	}{
+		{"kind", "HCT", ""},
+		{"kind", "hct", "invalid kind"},
+		{"version", "001", ""},
+		{"version", "1", "invalid version"},
+		{"charset", "2", ""},
+		{"charset", "x", "invalid charset"},
+		{"bic", "abcdefgh", ""},
+		{"bic", "abc", "invalid BIC length"},
+		{"amount", "HUF100", ""},
+		{"amount", "100", ""},
+		{"amount", "EUR100", "invalid amount"},
+		{"valid", "20200518101123+2", ""},
+		{"valid", "20200518101123", "invalid date length"},
+		{"purpose", "ACCT", ""},
+		{"loyaltyID", strings.Repeat("a", 35), ""},
+		{"loyaltyID", strings.Repeat("a", 36), "loyaltyID is too long"},
+	}
+
+	for _, tt := range testTable {
+		f, ok := LookupField(tt.field)
+		assert.True(t, ok)
+
+		err := f.Validate(tt.value)
+		if tt.expectedErr == "" {
+			assert.NoError(t, err, tt.field)
+		} else {
+			assert.EqualError(t, err, tt.expectedErr, tt.field)
+		}
+	}
+}
+
+func TestSetGet(t *testing.T) {
+	c := &Code{}
+
+	assert.EqualError(t, c.Set("something", "x"), "unknown field: something")
+	assert.NoError(t, c.Set("amount", "500"))
+	assert.Equal(t, "HUF500", c.Get("amount"))
+	assert.NoError(t, c.Set("bic", "abcdefgh"))
+	assert.Equal(t, "abcdefghXXX", c.Get("bic"))
+	assert.Equal(t, "", c.Get("something"))
+}
+
+func TestWriteFieldDocs(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteFieldDocs(&buf, Fields))
+
+	docs := buf.String()
+	assert.True(t, strings.HasPrefix(docs, "Required:\n- `kind`"))
+	assert.Contains(t, docs, "\nOptional:\n- `amount`")
+	assert.Contains(t, docs, "- `loyaltyID` - string (35 chars max, loyalty identifier)\n")
+
+	buf.Reset()
+	assert.NoError(t, WriteFieldDocs(&buf, EPCFields()))
+	assert.Contains(t, buf.String(), "\nOptional:\n- `bic`")
+}
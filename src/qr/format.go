@@ -0,0 +1,327 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Payload is a payment code of a format
+type Payload interface {
+	String() string
+	Set(name string, value string) error
+	Get(name string) string
+	Validate() error
+}
+
+// Format is a payment QR code standard
+type Format interface {
+	Name() string
+	Fields() []Field                        // Fields in payload order
+	New() Payload                           // Empty payload of the format
+	Encode(p Payload) (string, error)       // Validated content as it should be in the QR code
+	Decode(content string) (Payload, error) // Parse the content of the QR code
+	Validate(p Payload) error
+	Capacity() CapacityOptions // Limits of the format including the required error correction level
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = make(map[string]Format)
+)
+
+func init() {
+	RegisterFormat(mnbFormat{kind: KindHCT})
+	RegisterFormat(mnbFormat{kind: KindRTP})
+	RegisterFormat(epcFormat{})
+	RegisterFormat(spaydFormat{})
+	RegisterFormat(payBySquareFormat{})
+}
+
+// RegisterFormat makes a format available by its name, it panics if the name is already registered
+func RegisterFormat(f Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	name := strings.ToUpper(f.Name())
+	if _, ok := formats[name]; ok {
+		panic("qr: format registered twice: " + name)
+	}
+	formats[name] = f
+}
+
+// LookupFormat returns the format by its name (case insensitive)
+func LookupFormat(name string) (Format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	f, ok := formats[strings.ToUpper(name)]
+	return f, ok
+}
+
+// FormatNames returns the sorted names of the registered formats
+func FormatNames() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Detect tries to decode the content with all the registered formats
+func Detect(content string) (Format, Payload, error) {
+	for _, name := range FormatNames() {
+		f, _ := LookupFormat(name)
+		if p, err := f.Decode(content); err == nil {
+			return f, p, nil
+		}
+	}
+	return nil, nil, errors.New("unknown payment code format")
+}
+
+// WritePNG renders the payload of the format as a PNG image into the writer
+// Nothing is written if the code could not be generated.
+func WritePNG(w io.Writer, f Format, p Payload, opts RenderOptions) error {
+	content, err := f.Encode(p)
+	if err != nil {
+		return err
+	}
+
+	q, err := encode(content, opts.capacity(f.Capacity()))
+	if err != nil {
+		return err
+	}
+	return writePNG(w, q, opts, payloadReadBack(p, content))
+}
+
+// WriteSVG renders the payload of the format as an SVG image into the writer
+// Nothing is written if the code could not be generated.
+func WriteSVG(w io.Writer, f Format, p Payload, opts RenderOptions) error {
+	content, err := f.Encode(p)
+	if err != nil {
+		return err
+	}
+
+	q, err := encode(content, opts.capacity(f.Capacity()))
+	if err != nil {
+		return err
+	}
+	return writeVerifiedSVG(w, q, opts, payloadReadBack(p, content))
+}
+
+// payloadReadBack is the text and charset a reader gets back from the encoded content of the payload
+func payloadReadBack(p Payload, content string) readBack {
+	if e, ok := p.(*EPCCode); ok {
+		return e.readBack()
+	}
+	return readBack{text: content, charset: charsetUTF8}
+}
+
+// mnbFormat is the MNB QR code with a fixed kind
+type mnbFormat struct {
+	kind kind
+}
+
+// Name .
+func (m mnbFormat) Name() string {
+	return m.kind.String()
+}
+
+// Fields .
+func (m mnbFormat) Fields() []Field {
+	return Fields
+}
+
+// New .
+func (m mnbFormat) New() Payload {
+	return &Code{Kind: m.kind}
+}
+
+// Encode .
+func (m mnbFormat) Encode(p Payload) (string, error) {
+	if err := m.Validate(p); err != nil {
+		return "", err
+	}
+	return p.String(), nil
+}
+
+// Decode .
+func (m mnbFormat) Decode(content string) (Payload, error) {
+	c, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Kind != m.kind {
+		return nil, fmt.Errorf("kind should be %s", m.kind)
+	}
+	return c, nil
+}
+
+// Validate .
+func (m mnbFormat) Validate(p Payload) error {
+	c, ok := p.(*Code)
+	if !ok || c.Kind != m.kind {
+		return fmt.Errorf("payload is not a %s code", m.kind)
+	}
+	return c.Validate()
+}
+
+// Capacity .
+func (m mnbFormat) Capacity() CapacityOptions {
+	return DefaultCapacityOptions
+}
+
+// epcFormat is the SEPA credit transfer code
+type epcFormat struct{}
+
+// Name .
+func (epcFormat) Name() string {
+	return KindEPC.String()
+}
+
+// Fields .
+func (epcFormat) Fields() []Field {
+	return EPCFields()
+}
+
+// New .
+func (epcFormat) New() Payload {
+	return &EPCCode{Version: "002"}
+}
+
+// Encode .
+func (f epcFormat) Encode(p Payload) (string, error) {
+	if err := f.Validate(p); err != nil {
+		return "", err
+	}
+	return p.(*EPCCode).encoded()
+}
+
+// Decode .
+func (epcFormat) Decode(content string) (Payload, error) {
+	e, err := ParseEPC(content)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Validate .
+func (epcFormat) Validate(p Payload) error {
+	e, ok := p.(*EPCCode)
+	if !ok {
+		return errors.New("payload is not an EPC code")
+	}
+	return e.Validate()
+}
+
+// Capacity .
+func (epcFormat) Capacity() CapacityOptions {
+	return EPCCapacityOptions
+}
+
+// spaydFormat is the Czech Short Payment Descriptor
+type spaydFormat struct{}
+
+// Name .
+func (spaydFormat) Name() string {
+	return KindSPAYD.String()
+}
+
+// Fields .
+func (spaydFormat) Fields() []Field {
+	return SPAYDFields()
+}
+
+// New .
+func (spaydFormat) New() Payload {
+	return &SPAYDCode{}
+}
+
+// Encode .
+func (f spaydFormat) Encode(p Payload) (string, error) {
+	if err := f.Validate(p); err != nil {
+		return "", err
+	}
+	return p.String(), nil
+}
+
+// Decode .
+func (spaydFormat) Decode(content string) (Payload, error) {
+	s, err := ParseSPAYD(content)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate .
+func (spaydFormat) Validate(p Payload) error {
+	s, ok := p.(*SPAYDCode)
+	if !ok {
+		return errors.New("payload is not a SPAYD code")
+	}
+	return s.Validate()
+}
+
+// Capacity .
+func (spaydFormat) Capacity() CapacityOptions {
+	return SPAYDCapacityOptions
+}
+
+// payBySquareFormat is the Slovak PAY by square code
+type payBySquareFormat struct{}
+
+// Name .
+func (payBySquareFormat) Name() string {
+	return KindPayBySquare.String()
+}
+
+// Fields .
+func (payBySquareFormat) Fields() []Field {
+	return PayBySquareFields()
+}
+
+// New .
+func (payBySquareFormat) New() Payload {
+	return &PayBySquareCode{}
+}
+
+// Encode .
+func (f payBySquareFormat) Encode(p Payload) (string, error) {
+	if err := f.Validate(p); err != nil {
+		return "", err
+	}
+	return bySquareEncode(p.(*PayBySquareCode).tabbed())
+}
+
+// Decode .
+func (payBySquareFormat) Decode(content string) (Payload, error) {
+	p, err := ParsePayBySquare(content)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate .
+func (payBySquareFormat) Validate(p Payload) error {
+	b, ok := p.(*PayBySquareCode)
+	if !ok {
+		return errors.New("payload is not a PAY by square code")
+	}
+	return b.Validate()
+}
+
+// Capacity .
+func (payBySquareFormat) Capacity() CapacityOptions {
+	return PayBySquareCapacityOptions
+}
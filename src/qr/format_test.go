@@ -0,0 +1,137 @@
+package qr
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testFormat struct {
+	epcFormat
+}
+
+func (testFormat) Name() string {
+	return "test"
+}
+
+func TestFormatRegistry(t *testing.T) {
+	assert.Equal(t, []string{"EPC", "HCT", "PAYBYSQUARE", "RTP", "SPAYD"}, FormatNames())
+
+	f, ok := LookupFormat("hct")
+	assert.True(t, ok)
+	assert.Equal(t, "HCT", f.Name())
+	assert.Equal(t, DefaultCapacityOptions, f.Capacity())
+	assert.Equal(t, len(Fields), len(f.Fields()))
+
+	_, ok = LookupFormat("something")
+	assert.False(t, ok)
+
+	RegisterFormat(testFormat{})
+	defer func() {
+		formatsMu.Lock()
+		delete(formats, "TEST")
+		formatsMu.Unlock()
+	}()
+	_, ok = LookupFormat("TEST")
+	assert.True(t, ok)
+
+	assert.Panics(t, func() { RegisterFormat(testFormat{}) })
+}
+
+func TestFormatMNB(t *testing.T) {
+	f, _ := LookupFormat("RTP")
+
+	p := f.New()
+	assert.NoError(t, p.Set("bic", "abcdefgh"))
+	assert.NoError(t, p.Set("name", "Test User"))
+	assert.NoError(t, p.Set("iban", "HU00123456789012345678901234"))
+	assert.NoError(t, p.Set("message", "hello"))
+
+	_, err := f.Encode(p)
+	assert.EqualError(t, err, "negative validity period")
+
+	assert.NoError(t, p.(*Code).ValidUntil(time.Now().Add(time.Hour)))
+	content, err := f.Encode(p)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(content, "RTP\n"))
+
+	decoded, err := f.Decode(content)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", decoded.Get("message"))
+
+	hct, _ := LookupFormat("HCT")
+	_, err = hct.Decode(content)
+	assert.EqualError(t, err, "kind should be HCT")
+	assert.EqualError(t, hct.Validate(p), "payload is not a HCT code")
+
+	epc, _ := LookupFormat("EPC")
+	assert.EqualError(t, epc.Validate(p), "payload is not an EPC code")
+
+	assert.EqualError(t, f.Validate(&Code{Kind: KindRTP}), "bic is required")
+	assert.EqualError(t, (&Code{}).Validate(), "invalid kind")
+}
+
+func TestFormatEPC(t *testing.T) {
+	f, _ := LookupFormat("epc")
+
+	p, err := f.Decode(epcExample)
+	assert.NoError(t, err)
+	assert.Equal(t, EPCCapacityOptions, f.Capacity())
+
+	content, err := f.Encode(p)
+	assert.NoError(t, err)
+	assert.Equal(t, epcExample, content)
+
+	_, err = f.Decode("BCD")
+	assert.EqualError(t, err, "invalid line count: 1")
+
+	assert.Equal(t, "002", f.New().Get("version"))
+}
+
+func TestDetect(t *testing.T) {
+	f, p, err := Detect(epcExample)
+	assert.NoError(t, err)
+	assert.Equal(t, "EPC", f.Name())
+	assert.Equal(t, "DE33100205000001194700", p.Get("iban"))
+
+	c := genValidCode(t)
+	f, p, err = Detect(c.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "HCT", f.Name())
+	assert.Equal(t, "HUF500", p.Get("amount"))
+
+	// The output of the generator, printed with fmt.Println
+	f, p, err = Detect(c.String() + "\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "HCT", f.Name())
+	assert.Equal(t, "HUF500", p.Get("amount"))
+
+	_, _, err = Detect("something")
+	assert.EqualError(t, err, "unknown payment code format")
+}
+
+func TestFormatWrite(t *testing.T) {
+	f, p, err := Detect(epcExample)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WritePNG(&buf, f, p, RenderOptions{Size: 128}))
+	assert.True(t, buf.Len() > 100)
+
+	buf.Reset()
+	assert.NoError(t, WriteSVG(&buf, f, p, RenderOptions{Size: 128}))
+	assert.True(t, strings.HasPrefix(buf.String(), "<svg"))
+
+	hct, _ := LookupFormat("HCT")
+	buf.Reset()
+	assert.EqualError(t, WritePNG(&buf, hct, p, RenderOptions{Size: 128}), "payload is not a HCT code")
+	assert.EqualError(t, WriteSVG(&buf, hct, p, RenderOptions{Size: 128}), "payload is not a HCT code")
+	assert.Equal(t, 0, buf.Len())
+
+	// Over the format limits
+	c := genFullCode(t)
+	assert.EqualError(t, WritePNG(&buf, hct, c, RenderOptions{Size: 128}), "qr content is too large: 483 bytes, maximum is 345")
+}
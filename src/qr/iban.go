@@ -0,0 +1,87 @@
+package qr
+
+import (
+	"errors"
+	"strconv"
+)
+
+// ValidateIBAN checks the structure and the mod-97 check digits of an IBAN (without spaces)
+func ValidateIBAN(iban string) error {
+	if len(iban) < 15 || len(iban) > 34 {
+		return errors.New("invalid IBAN length")
+	}
+
+	for i, r := range iban {
+		switch {
+		case i < 2 && (r < 'A' || r > 'Z'):
+			return errors.New("invalid IBAN country code")
+		case i >= 2 && i < 4 && (r < '0' || r > '9'):
+			return errors.New("invalid IBAN check digits")
+		case (r < '0' || r > '9') && (r < 'A' || r > 'Z'):
+			return errors.New("invalid IBAN character")
+		}
+	}
+
+	if mod97(iban[4:]+iban[:4]) != 1 {
+		return errors.New("invalid IBAN checksum")
+	}
+	return nil
+}
+
+// mod97 calculates the ISO 7064 mod 97-10 remainder, letters are converted to numbers (A = 10)
+func mod97(s string) int {
+	remainder := 0
+	for _, r := range s {
+		var digits string
+		if r >= 'A' && r <= 'Z' {
+			digits = strconv.Itoa(int(r-'A') + 10)
+		} else {
+			digits = string(r)
+		}
+
+		for _, d := range digits {
+			remainder = (remainder*10 + int(d-'0')) % 97
+		}
+	}
+	return remainder
+}
+
+// HungarianIBAN converts a Hungarian account number (2x8 or 3x8 digits, with or without separators) to IBAN
+// The check digits of the account number are validated too.
+func HungarianIBAN(account string) (string, error) {
+	bban := make([]byte, 0, 24)
+	for _, r := range account {
+		switch {
+		case r >= '0' && r <= '9':
+			bban = append(bban, byte(r))
+		case r == '-' || r == ' ':
+		default:
+			return "", errors.New("invalid account number character")
+		}
+	}
+
+	switch len(bban) {
+	case 16:
+		bban = append(bban, "00000000"...)
+	case 24:
+	default:
+		return "", errors.New("invalid account number length")
+	}
+
+	if !hungarianCheck(bban[:8]) || !hungarianCheck(bban[8:]) {
+		return "", errors.New("invalid account number checksum")
+	}
+
+	check := 98 - mod97(string(bban)+"HU00")
+	return "HU" + strconv.Itoa(check/10) + strconv.Itoa(check%10) + string(bban), nil
+}
+
+// hungarianCheck validates the 9, 7, 3, 1 weighted check digit of an account number part
+func hungarianCheck(digits []byte) bool {
+	weights := []int{9, 7, 3, 1}
+	sum := 0
+	for i, d := range digits {
+		sum += int(d-'0') * weights[i%4]
+	}
+	return sum%10 == 0
+}
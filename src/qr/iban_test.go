@@ -0,0 +1,63 @@
+package qr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateIBAN(t *testing.T) {
+	testTable := []struct {
+		input       string
+		expectedErr string
+	}{
+		{"HU42117730161111101800000000", ""},
+		{"DE89370400440532013000", ""},
+		{"GB82WEST12345698765432", ""},
+		{"CZ6508000000192000145399", ""},
+		{"HU00123456789012345678901234", "invalid IBAN checksum"},
+		{"DE89370400440532013001", "invalid IBAN checksum"},
+		{"DE8937040044", "invalid IBAN length"},
+		{"de89370400440532013000", "invalid IBAN country code"},
+		{"DEX9370400440532013000", "invalid IBAN check digits"},
+		{"DE89 370400440532013000", "invalid IBAN character"},
+	}
+
+	for _, tt := range testTable {
+		err := ValidateIBAN(tt.input)
+		if tt.expectedErr == "" {
+			assert.NoError(t, err, tt.input)
+		} else {
+			assert.EqualError(t, err, tt.expectedErr, tt.input)
+		}
+	}
+}
+
+func TestHungarianIBAN(t *testing.T) {
+	testTable := []struct {
+		input       string
+		expected    string
+		expectedErr string
+	}{
+		{"11773016-11111018-00000000", "HU42117730161111101800000000", ""},
+		{"11773016-11111018", "HU42117730161111101800000000", ""},
+		{"117730161111101800000000", "HU42117730161111101800000000", ""},
+		{"11600006 00000000 12345676", "HU93116000060000000012345676", ""},
+		{"11773016-1111101", "", "invalid account number length"},
+		{"11773016/11111018", "", "invalid account number character"},
+		{"11773017-11111018", "", "invalid account number checksum"},
+		{"11773016-11111019", "", "invalid account number checksum"},
+	}
+
+	for _, tt := range testTable {
+		iban, err := HungarianIBAN(tt.input)
+		if tt.expectedErr != "" {
+			assert.EqualError(t, err, tt.expectedErr, tt.input)
+			continue
+		}
+
+		assert.NoError(t, err, tt.input)
+		assert.Equal(t, tt.expected, iban)
+		assert.NoError(t, ValidateIBAN(iban))
+	}
+}
@@ -0,0 +1,394 @@
+package qr
+
+import (
+	"errors"
+	"strings"
+)
+
+// Purpose code categories, the classification of the ISO code set
+const (
+	CategoryCardSettlement = "Card Settlement"
+	CategoryCashManagement = "Cash Mgmt"
+	CategoryCollateral     = "Collateral"
+	CategoryCommercial     = "Commercial"
+	CategoryConsumer       = "Consumer"
+	CategoryEpayment       = "Epayment"
+	CategoryFinance        = "Finance"
+	CategoryGeneral        = "General"
+	CategoryInsurance      = "Insurance"
+	CategoryInvestment     = "Investment"
+	CategoryMedical        = "Medical"
+	CategorySalaryBenefits = "Salary & Benefits"
+	CategoryTax            = "Tax"
+	CategoryTransport      = "Transport"
+	CategoryTreasury       = "Treasury"
+	CategoryUtilities      = "Utilities"
+)
+
+// PurposeCode is an entry of the ISO 20022 external purpose code list (AT-44)
+type PurposeCode struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`   // English name
+	NameHU   string `json:"nameHU"` // Hungarian name
+	Category string `json:"category"`
+}
+
+// PurposeCodes catalogue ordered by the code, the Hungarian names are ours
+// Source: ExternalPurpose1Code of the ISO 20022 external code sets, 1Q2024 release
+// https://www.iso20022.org/catalogue-messages/additional-content-messages/external-code-sets
+var PurposeCodes = []PurposeCode{
+	{Code: "ACCT", Name: "Account management", NameHU: "Számlakezelés", Category: CategoryCashManagement},
+	{Code: "ADCS", Name: "Advisory donation copyright services", NameHU: "Tanácsadói, adományozási és szerzői jogi szolgáltatás", Category: CategoryGeneral},
+	{Code: "ADMG", Name: "Administrative management", NameHU: "Adminisztratív ügyintézés", Category: CategoryGeneral},
+	{Code: "ADVA", Name: "Advance payment", NameHU: "Előlegfizetés", Category: CategoryGeneral},
+	{Code: "AEMP", Name: "Active employment policy", NameHU: "Aktív foglalkoztatáspolitikai kifizetés", Category: CategorySalaryBenefits},
+	{Code: "AGRT", Name: "Agricultural transfer", NameHU: "Mezőgazdasági utalás", Category: CategoryCommercial},
+	{Code: "AIRB", Name: "Air", NameHU: "Légi közlekedés", Category: CategoryTransport},
+	{Code: "ALLW", Name: "Allowance", NameHU: "Juttatás", Category: CategorySalaryBenefits},
+	{Code: "ALMY", Name: "Alimony payment", NameHU: "Tartásdíj fizetés", Category: CategorySalaryBenefits},
+	{Code: "AMEX", Name: "Amex", NameHU: "Amex kártyás elszámolás", Category: CategoryCardSettlement},
+	{Code: "ANNI", Name: "Annuity", NameHU: "Járadék", Category: CategoryFinance},
+	{Code: "ANTS", Name: "Anesthesia services", NameHU: "Aneszteziológiai szolgáltatás", Category: CategoryMedical},
+	{Code: "AREN", Name: "Accounts receivables entry", NameHU: "Követelés elszámolás", Category: CategoryCommercial},
+	{Code: "AUCO", Name: "Authenticated collections", NameHU: "Hitelesített beszedés", Category: CategoryCommercial},
+	{Code: "B112", Name: "Trailer fee payment", NameHU: "Jutalékfizetés befektetési alap után", Category: CategoryInvestment},
+	{Code: "BBSC", Name: "Baby bonus scheme", NameHU: "Babaváró támogatás", Category: CategorySalaryBenefits},
+	{Code: "BCDM", Name: "Bearer cheque domestic", NameHU: "Belföldi bemutatóra szóló csekk", Category: CategoryGeneral},
+	{Code: "BCFG", Name: "Bearer cheque foreign", NameHU: "Külföldi bemutatóra szóló csekk", Category: CategoryGeneral},
+	{Code: "BECH", Name: "Child benefit", NameHU: "Családi pótlék", Category: CategorySalaryBenefits},
+	{Code: "BENE", Name: "Unemployment disability benefit", NameHU: "Munkanélküli vagy rokkantsági ellátás", Category: CategorySalaryBenefits},
+	{Code: "BEXP", Name: "Business expenses", NameHU: "Üzleti költségek", Category: CategoryCommercial},
+	{Code: "BFWD", Name: "Bond forward", NameHU: "Határidős kötvényügylet", Category: CategoryTreasury},
+	{Code: "BKDF", Name: "Bank loan delayed draw funding", NameHU: "Banki hitel késleltetett lehívása", Category: CategoryFinance},
+	{Code: "BKFE", Name: "Bank loan fees", NameHU: "Banki hitel díjai", Category: CategoryFinance},
+	{Code: "BKFM", Name: "Bank loan funding memo", NameHU: "Banki hitel folyósítási értesítő", Category: CategoryFinance},
+	{Code: "BKIP", Name: "Bank loan accrued interest payment", NameHU: "Banki hitel felhalmozott kamatának fizetése", Category: CategoryFinance},
+	{Code: "BKPP", Name: "Bank loan principal paydown", NameHU: "Banki hitel tőketörlesztése", Category: CategoryFinance},
+	{Code: "BLDM", Name: "Building maintenance", NameHU: "Épületfenntartás", Category: CategoryConsumer},
+	{Code: "BNET", Name: "Bond forward netting", NameHU: "Határidős kötvényügyletek nettósítása", Category: CategoryTreasury},
+	{Code: "BOCE", Name: "Back office conversion entry", NameHU: "Back office konverziós tétel", Category: CategoryCommercial},
+	{Code: "BONU", Name: "Bonus payment", NameHU: "Prémium kifizetés", Category: CategorySalaryBenefits},
+	{Code: "BR12", Name: "Broker fee", NameHU: "Brókerdíj", Category: CategoryInvestment},
+	{Code: "BUSB", Name: "Bus", NameHU: "Autóbusz közlekedés", Category: CategoryTransport},
+	{Code: "CAFI", Name: "Custodian management fee in-house", NameHU: "Letétkezelési díj, házon belül", Category: CategoryInvestment},
+	{Code: "CASH", Name: "Cash management transfer", NameHU: "Készpénzkezelési utalás", Category: CategoryCashManagement},
+	{Code: "CBFF", Name: "Capital building", NameHU: "Tőkefelhalmozás", Category: CategorySalaryBenefits},
+	{Code: "CBFR", Name: "Capital building retirement", NameHU: "Nyugdíjcélú tőkefelhalmozás", Category: CategorySalaryBenefits},
+	{Code: "CBLK", Name: "Card bulk clearing", NameHU: "Kártyás tömeges elszámolás", Category: CategoryCardSettlement},
+	{Code: "CBTV", Name: "Cable TV bill", NameHU: "Kábeltelevízió számla", Category: CategoryUtilities},
+	{Code: "CCHD", Name: "Cash compensation helplessness disability", NameHU: "Rokkantsági és gondozási pénzbeli ellátás", Category: CategorySalaryBenefits},
+	{Code: "CCIR", Name: "Cross currency IRS", NameHU: "Devizák közötti kamatcsere", Category: CategoryTreasury},
+	{Code: "CCPC", Name: "CCP cleared initial margin", NameHU: "Központi szerződő fél által elszámolt kezdeti letét", Category: CategoryCollateral},
+	{Code: "CCPM", Name: "CCP cleared variation margin", NameHU: "Központi szerződő fél által elszámolt változó letét", Category: CategoryCollateral},
+	{Code: "CCRD", Name: "Credit card payment", NameHU: "Hitelkártyás fizetés", Category: CategoryCardSettlement},
+	{Code: "CCSM", Name: "CCP cleared initial margin segregated cash", NameHU: "Központi szerződő fél elkülönített kezdeti készpénzletétje", Category: CategoryCollateral},
+	{Code: "CDBL", Name: "Credit card bill", NameHU: "Hitelkártya számla", Category: CategoryConsumer},
+	{Code: "CDCB", Name: "Card payment with cash back", NameHU: "Kártyás fizetés készpénzfelvétellel", Category: CategoryCardSettlement},
+	{Code: "CDCD", Name: "Cash disbursement cash settlement", NameHU: "Kártyás készpénzfelvétel elszámolása", Category: CategoryCardSettlement},
+	{Code: "CDCS", Name: "Cash disbursement with surcharging", NameHU: "Kártyás készpénzfelvétel felárral", Category: CategoryCardSettlement},
+	{Code: "CDDP", Name: "Card deferred payment", NameHU: "Halasztott kártyás fizetés", Category: CategoryCardSettlement},
+	{Code: "CDEP", Name: "Credit default event payment", NameHU: "Hitelnemteljesítési eseményhez kapcsolódó fizetés", Category: CategoryTreasury},
+	{Code: "CDOC", Name: "Original credit", NameHU: "Eredeti jóváírás", Category: CategoryCardSettlement},
+	{Code: "CDQC", Name: "Quasi cash", NameHU: "Készpénz-helyettesítő", Category: CategoryCardSettlement},
+	{Code: "CFDI", Name: "Capital falling due in-house", NameHU: "Lejáró tőke, házon belül", Category: CategoryInvestment},
+	{Code: "CFEE", Name: "Cancellation fee", NameHU: "Lemondási díj", Category: CategoryGeneral},
+	{Code: "CGDD", Name: "Card generated direct debit", NameHU: "Kártyával kezdeményezett beszedés", Category: CategoryCardSettlement},
+	{Code: "CHAR", Name: "Charity payment", NameHU: "Jótékonysági adomány", Category: CategoryConsumer},
+	{Code: "CLPR", Name: "Car loan principal repayment", NameHU: "Gépjárműhitel tőketörlesztés", Category: CategoryFinance},
+	{Code: "CMDT", Name: "Commodity transfer", NameHU: "Árupiaci utalás", Category: CategoryTreasury},
+	{Code: "COLL", Name: "Collection payment", NameHU: "Beszedési fizetés", Category: CategoryCashManagement},
+	{Code: "COMC", Name: "Commercial payment", NameHU: "Kereskedelmi fizetés", Category: CategoryCommercial},
+	{Code: "COMM", Name: "Commission", NameHU: "Jutalék", Category: CategoryCommercial},
+	{Code: "COMP", Name: "Compensation payment", NameHU: "Kártérítés, kompenzáció", Category: CategoryCommercial},
+	{Code: "COMT", Name: "Consumer third party consolidated payment", NameHU: "Fogyasztói harmadik feles összevont fizetés", Category: CategoryConsumer},
+	{Code: "CORT", Name: "Trade settlement payment", NameHU: "Kereskedelmi ügylet elszámolása", Category: CategoryTreasury},
+	{Code: "COST", Name: "Costs", NameHU: "Költségek", Category: CategoryGeneral},
+	{Code: "CPEN", Name: "Cash penalties", NameHU: "Pénzbeli kötbér", Category: CategoryInvestment},
+	{Code: "CPKC", Name: "Carpark charges", NameHU: "Parkolási díj", Category: CategoryTransport},
+	{Code: "CPYR", Name: "Copyright", NameHU: "Szerzői jogdíj", Category: CategoryCommercial},
+	{Code: "CRDS", Name: "Credit default swap", NameHU: "Hitel-nemteljesítési csereügylet", Category: CategoryTreasury},
+	{Code: "CRPR", Name: "Cross product", NameHU: "Termékek közötti ügylet", Category: CategoryTreasury},
+	{Code: "CRSP", Name: "Credit support", NameHU: "Hitelbiztosíték", Category: CategoryTreasury},
+	{Code: "CRTL", Name: "Credit line", NameHU: "Hitelkeret", Category: CategoryTreasury},
+	{Code: "CSDB", Name: "Cash disbursement", NameHU: "Készpénz kifizetés", Category: CategoryCashManagement},
+	{Code: "CSLP", Name: "Company social loan payment to bank", NameHU: "Munkáltatói szociális kölcsön törlesztése", Category: CategoryFinance},
+	{Code: "CVCF", Name: "Convalescent care facility", NameHU: "Lábadozó ellátás", Category: CategoryMedical},
+	{Code: "DBTC", Name: "Debit collection payment", NameHU: "Terheléses beszedés", Category: CategoryCashManagement},
+	{Code: "DCRD", Name: "Debit card payment", NameHU: "Betéti kártyás fizetés", Category: CategoryCardSettlement},
+	{Code: "DEPD", Name: "Dependent support payment", NameHU: "Eltartott személy támogatása", Category: CategorySalaryBenefits},
+	{Code: "DEPT", Name: "Deposit", NameHU: "Betét", Category: CategoryCashManagement},
+	{Code: "DERI", Name: "Derivatives", NameHU: "Származtatott ügylet", Category: CategoryTreasury},
+	{Code: "DIVD", Name: "Dividend", NameHU: "Osztalék", Category: CategoryInvestment},
+	{Code: "DMEQ", Name: "Durable medical equipment", NameHU: "Tartós gyógyászati segédeszköz", Category: CategoryMedical},
+	{Code: "DNTS", Name: "Dental services", NameHU: "Fogászati szolgáltatás", Category: CategoryMedical},
+	{Code: "DSMT", Name: "Printed order disbursement", NameHU: "Nyomtatott utalvány kifizetése", Category: CategoryGeneral},
+	{Code: "DVPM", Name: "Deliver against payment", NameHU: "Fizetés ellenében történő szállítás", Category: CategoryInvestment},
+	{Code: "ECPG", Name: "Guaranteed e-payment", NameHU: "Garantált e-fizetés", Category: CategoryEpayment},
+	{Code: "ECPR", Name: "E-payment return", NameHU: "E-fizetés visszautalása", Category: CategoryEpayment},
+	{Code: "ECPU", Name: "Non-guaranteed e-payment", NameHU: "Nem garantált e-fizetés", Category: CategoryEpayment},
+	{Code: "EDUC", Name: "Education", NameHU: "Oktatás", Category: CategoryConsumer},
+	{Code: "EFTC", Name: "Low value credit", NameHU: "Kis értékű jóváírás", Category: CategoryGeneral},
+	{Code: "EFTD", Name: "Low value debit", NameHU: "Kis értékű terhelés", Category: CategoryGeneral},
+	{Code: "ELEC", Name: "Electricity bill", NameHU: "Villanyszámla", Category: CategoryUtilities},
+	{Code: "ENRG", Name: "Energies", NameHU: "Energia", Category: CategoryUtilities},
+	{Code: "EPAY", Name: "E-payment", NameHU: "Elektronikus fizetés", Category: CategoryEpayment},
+	{Code: "EQPT", Name: "Equity option", NameHU: "Részvényopció", Category: CategoryTreasury},
+	{Code: "EQTS", Name: "Equity", NameHU: "Részvény", Category: CategoryTreasury},
+	{Code: "EQUS", Name: "Equity swap", NameHU: "Részvénycsere-ügylet", Category: CategoryTreasury},
+	{Code: "ESTX", Name: "Estate tax", NameHU: "Örökösödési illeték", Category: CategoryTax},
+	{Code: "ETUP", Name: "E-purse top up", NameHU: "Elektronikus pénztárca feltöltés", Category: CategoryConsumer},
+	{Code: "EXPT", Name: "Exotic option", NameHU: "Egzotikus opció", Category: CategoryTreasury},
+	{Code: "EXTD", Name: "Exchange traded derivatives", NameHU: "Tőzsdei származtatott ügylet", Category: CategoryTreasury},
+	{Code: "FACT", Name: "Factor update related payment", NameHU: "Faktorfrissítéshez kapcsolódó fizetés", Category: CategoryTreasury},
+	{Code: "FAND", Name: "Financial aid in case of natural disaster", NameHU: "Természeti katasztrófa miatti pénzügyi segély", Category: CategoryGeneral},
+	{Code: "FCIN", Name: "Fee collection and interest", NameHU: "Díj és kamat beszedése", Category: CategoryFinance},
+	{Code: "FCOL", Name: "Fee collection", NameHU: "Díjbeszedés", Category: CategoryCardSettlement},
+	{Code: "FCPM", Name: "Late payment of fees and charges", NameHU: "Díjak és költségek késedelmes megfizetése", Category: CategoryFinance},
+	{Code: "FERB", Name: "Ferry", NameHU: "Komp", Category: CategoryTransport},
+	{Code: "FIXI", Name: "Fixed income", NameHU: "Fix hozamú ügylet", Category: CategoryTreasury},
+	{Code: "FNET", Name: "Futures netting payment", NameHU: "Határidős ügyletek nettósítása", Category: CategoryTreasury},
+	{Code: "FORW", Name: "Forward foreign exchange", NameHU: "Határidős devizaügylet", Category: CategoryTreasury},
+	{Code: "FREX", Name: "Foreign exchange", NameHU: "Devizaváltás", Category: CategoryTreasury},
+	{Code: "FUTR", Name: "Futures", NameHU: "Határidős ügylet", Category: CategoryTreasury},
+	{Code: "FWBC", Name: "Forward broker owned cash collateral", NameHU: "Határidős ügylet bróker tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "FWCC", Name: "Forward client owned cash collateral", NameHU: "Határidős ügylet ügyfél tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "FWLV", Name: "Foreign worker levy", NameHU: "Külföldi munkavállalók utáni járulék", Category: CategoryTax},
+	{Code: "FWSB", Name: "Forward broker owned cash collateral segregated", NameHU: "Határidős ügylet bróker tulajdonú elkülönített készpénzfedezete", Category: CategoryCollateral},
+	{Code: "FWSC", Name: "Forward client owned segregated cash collateral", NameHU: "Határidős ügylet ügyfél tulajdonú elkülönített készpénzfedezete", Category: CategoryCollateral},
+	{Code: "FXNT", Name: "Foreign exchange related netting", NameHU: "Devizaügyletek nettósítása", Category: CategoryTreasury},
+	{Code: "GAFA", Name: "Government family allowance", NameHU: "Állami családtámogatás", Category: CategorySalaryBenefits},
+	{Code: "GAHO", Name: "Government housing allowance", NameHU: "Állami lakhatási támogatás", Category: CategorySalaryBenefits},
+	{Code: "GAMB", Name: "Gambling or wagering payment", NameHU: "Szerencsejáték vagy fogadás", Category: CategoryConsumer},
+	{Code: "GASB", Name: "Gas bill", NameHU: "Gázszámla", Category: CategoryUtilities},
+	{Code: "GDDS", Name: "Purchase sale of goods", NameHU: "Áruvásárlás", Category: CategoryCommercial},
+	{Code: "GDSV", Name: "Purchase sale of goods and services", NameHU: "Áru- és szolgáltatásvásárlás", Category: CategoryCommercial},
+	{Code: "GFRP", Name: "Guarantee fund rights payment", NameHU: "Garanciaalapból történő kifizetés", Category: CategorySalaryBenefits},
+	{Code: "GIFT", Name: "Gift", NameHU: "Ajándék", Category: CategoryConsumer},
+	{Code: "GOVI", Name: "Government insurance", NameHU: "Állami biztosítás", Category: CategoryInsurance},
+	{Code: "GOVT", Name: "Government payment", NameHU: "Állami kifizetés", Category: CategoryGeneral},
+	{Code: "GSCB", Name: "Purchase sale of goods and services with cash back", NameHU: "Áru- és szolgáltatásvásárlás készpénzfelvétellel", Category: CategoryCommercial},
+	{Code: "GSTX", Name: "Goods and services tax", NameHU: "Általános forgalmi adó (GST)", Category: CategoryTax},
+	{Code: "GVEA", Name: "Austrian government employees category A", NameHU: "Osztrák közalkalmazottak, A kategória", Category: CategorySalaryBenefits},
+	{Code: "GVEB", Name: "Austrian government employees category B", NameHU: "Osztrák közalkalmazottak, B kategória", Category: CategorySalaryBenefits},
+	{Code: "GVEC", Name: "Austrian government employees category C", NameHU: "Osztrák közalkalmazottak, C kategória", Category: CategorySalaryBenefits},
+	{Code: "GVED", Name: "Austrian government employees category D", NameHU: "Osztrák közalkalmazottak, D kategória", Category: CategorySalaryBenefits},
+	{Code: "GWLT", Name: "Government war legislation transfer", NameHU: "Háborús jogszabályon alapuló állami kifizetés", Category: CategorySalaryBenefits},
+	{Code: "HEDG", Name: "Hedging", NameHU: "Fedezeti ügylet", Category: CategoryTreasury},
+	{Code: "HLRP", Name: "Property loan repayment", NameHU: "Lakáshitel törlesztés", Category: CategoryFinance},
+	{Code: "HLST", Name: "Property loan settlement", NameHU: "Lakáshitel végtörlesztés", Category: CategoryFinance},
+	{Code: "HLTC", Name: "Home health care", NameHU: "Otthoni ápolás", Category: CategoryMedical},
+	{Code: "HLTI", Name: "Health insurance", NameHU: "Egészségbiztosítás", Category: CategoryInsurance},
+	{Code: "HREC", Name: "Housing related contribution", NameHU: "Lakhatási hozzájárulás", Category: CategorySalaryBenefits},
+	{Code: "HSPC", Name: "Hospital care", NameHU: "Kórházi ellátás", Category: CategoryMedical},
+	{Code: "HSTX", Name: "Housing tax", NameHU: "Lakásadó", Category: CategoryTax},
+	{Code: "ICCP", Name: "Irrevocable credit card payment", NameHU: "Visszavonhatatlan hitelkártyás fizetés", Category: CategoryCardSettlement},
+	{Code: "ICRF", Name: "Intermediate care facility", NameHU: "Átmeneti gondozás", Category: CategoryMedical},
+	{Code: "IDCP", Name: "Irrevocable debit card payment", NameHU: "Visszavonhatatlan betéti kártyás fizetés", Category: CategoryCardSettlement},
+	{Code: "IHRP", Name: "Instalment hire purchase agreement", NameHU: "Részletvásárlási szerződés", Category: CategoryFinance},
+	{Code: "INPC", Name: "Insurance premium car", NameHU: "Gépjármű-biztosítási díj", Category: CategoryInsurance},
+	{Code: "INPR", Name: "Insurance premium refund", NameHU: "Biztosítási díj visszatérítése", Category: CategoryInsurance},
+	{Code: "INSC", Name: "Payment of insurance claim", NameHU: "Biztosítási kártérítés kifizetése", Category: CategoryInsurance},
+	{Code: "INSM", Name: "Installment", NameHU: "Részletfizetés", Category: CategoryFinance},
+	{Code: "INSU", Name: "Insurance premium", NameHU: "Biztosítási díj", Category: CategoryInsurance},
+	{Code: "INTC", Name: "Intra company payment", NameHU: "Vállalatcsoporton belüli fizetés", Category: CategoryCashManagement},
+	{Code: "INTE", Name: "Interest", NameHU: "Kamat", Category: CategoryFinance},
+	{Code: "INTP", Name: "Intra party payment", NameHU: "Azonos ügyfél számlái közötti fizetés", Category: CategoryCashManagement},
+	{Code: "INTX", Name: "Income tax", NameHU: "Jövedelemadó", Category: CategoryTax},
+	{Code: "INVS", Name: "Investment and securities", NameHU: "Befektetés és értékpapír", Category: CategoryInvestment},
+	{Code: "IVPT", Name: "Invoice payment", NameHU: "Számla kiegyenlítése", Category: CategoryCommercial},
+	{Code: "LBIN", Name: "Lending buy-in netting", NameHU: "Kölcsönügyletek kényszervásárlásának nettósítása", Category: CategoryInvestment},
+	{Code: "LBRI", Name: "Labor insurance", NameHU: "Munkavállalói biztosítás", Category: CategoryInsurance},
+	{Code: "LCOL", Name: "Lending cash collateral free movement", NameHU: "Kölcsönügylet készpénzfedezetének szabad mozgatása", Category: CategoryCollateral},
+	{Code: "LFEE", Name: "Lending fees", NameHU: "Értékpapír-kölcsönzési díjak", Category: CategoryInvestment},
+	{Code: "LICF", Name: "License fee", NameHU: "Licencdíj", Category: CategoryCommercial},
+	{Code: "LIFI", Name: "Life insurance", NameHU: "Életbiztosítás", Category: CategoryInsurance},
+	{Code: "LIMA", Name: "Liquidity management", NameHU: "Likviditáskezelés", Category: CategoryCashManagement},
+	{Code: "LMEQ", Name: "Lending equity marked-to-market cash collateral", NameHU: "Részvénykölcsön piaci értékelésű készpénzfedezete", Category: CategoryCollateral},
+	{Code: "LMFI", Name: "Lending fixed income marked-to-market cash collateral", NameHU: "Kötvénykölcsön piaci értékelésű készpénzfedezete", Category: CategoryCollateral},
+	{Code: "LMRK", Name: "Lending unspecified type of marked-to-market cash collateral", NameHU: "Kölcsönügylet egyéb piaci értékelésű készpénzfedezete", Category: CategoryCollateral},
+	{Code: "LOAN", Name: "Loan", NameHU: "Kölcsön", Category: CategoryFinance},
+	{Code: "LOAR", Name: "Loan repayment", NameHU: "Kölcsön törlesztés", Category: CategoryFinance},
+	{Code: "LOTT", Name: "Lottery payment", NameHU: "Lottónyeremény, sorsjáték", Category: CategoryConsumer},
+	{Code: "LREB", Name: "Lending rebate payments", NameHU: "Értékpapír-kölcsönzési visszatérítés", Category: CategoryInvestment},
+	{Code: "LREV", Name: "Lending revenue payments", NameHU: "Értékpapír-kölcsönzési bevétel", Category: CategoryInvestment},
+	{Code: "LSFL", Name: "Lending claim payment", NameHU: "Értékpapír-kölcsönzési követelés kifizetése", Category: CategoryInvestment},
+	{Code: "LTCF", Name: "Long term care facility", NameHU: "Tartós ápolási intézmény", Category: CategoryMedical},
+	{Code: "MAFC", Name: "Medical aid fund contribution", NameHU: "Egészségpénztári befizetés", Category: CategorySalaryBenefits},
+	{Code: "MARF", Name: "Medical aid refund", NameHU: "Egészségpénztári visszatérítés", Category: CategorySalaryBenefits},
+	{Code: "MARG", Name: "Daily margin on listed derivatives", NameHU: "Tőzsdei származtatott ügyletek napi letéte", Category: CategoryCollateral},
+	{Code: "MBSB", Name: "MBS broker owned cash collateral", NameHU: "Jelzálogfedezetű értékpapír bróker tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "MBSC", Name: "MBS client owned cash collateral", NameHU: "Jelzálogfedezetű értékpapír ügyfél tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "MCDM", Name: "Corporate action multi-currency debit memo", NameHU: "Vállalati esemény többdevizás terhelési értesítője", Category: CategoryInvestment},
+	{Code: "MDCS", Name: "Medical services", NameHU: "Egészségügyi szolgáltatás", Category: CategoryMedical},
+	{Code: "MGCC", Name: "Futures initial margin", NameHU: "Határidős ügyletek kezdeti letéte", Category: CategoryCollateral},
+	{Code: "MGSC", Name: "Futures initial margin client owned segregated cash collateral", NameHU: "Határidős ügyletek ügyfél tulajdonú elkülönített kezdeti letéte", Category: CategoryCollateral},
+	{Code: "MOMA", Name: "Money market", NameHU: "Pénzpiaci ügylet", Category: CategoryTreasury},
+	{Code: "MP2B", Name: "Mobile P2B payment", NameHU: "Mobil fizetés magánszemélytől vállalkozásnak", Category: CategoryCommercial},
+	{Code: "MP2P", Name: "Mobile P2P payment", NameHU: "Mobil fizetés magánszemélyek között", Category: CategoryConsumer},
+	{Code: "MSVC", Name: "Multiple service types", NameHU: "Többféle szolgáltatás", Category: CategoryGeneral},
+	{Code: "MTUP", Name: "Mobile top up", NameHU: "Mobil egyenleg feltöltés", Category: CategoryConsumer},
+	{Code: "NETT", Name: "Netting", NameHU: "Nettósítás", Category: CategoryCashManagement},
+	{Code: "NITX", Name: "Net income tax", NameHU: "Nettó jövedelemadó", Category: CategoryTax},
+	{Code: "NOWS", Name: "Not otherwise specified", NameHU: "Másként nem meghatározott", Category: CategoryGeneral},
+	{Code: "NWCH", Name: "Network charge", NameHU: "Hálózati díj", Category: CategoryUtilities},
+	{Code: "NWCM", Name: "Network communication", NameHU: "Hálózati kommunikáció", Category: CategoryUtilities},
+	{Code: "OCCC", Name: "Client owned OCC pledged collateral", NameHU: "Ügyfél tulajdonú, OCC-nek elzálogosított fedezet", Category: CategoryCollateral},
+	{Code: "OCDM", Name: "Order cheque domestic", NameHU: "Belföldi rendeleti csekk", Category: CategoryGeneral},
+	{Code: "OCFG", Name: "Order cheque foreign", NameHU: "Külföldi rendeleti csekk", Category: CategoryGeneral},
+	{Code: "OFEE", Name: "Opening fee", NameHU: "Nyitási díj", Category: CategoryGeneral},
+	{Code: "OPBC", Name: "OTC option broker owned cash collateral", NameHU: "Tőzsdén kívüli opció bróker tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "OPCC", Name: "OTC option client owned cash collateral", NameHU: "Tőzsdén kívüli opció ügyfél tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "OPSB", Name: "OTC option broker owned segregated cash collateral", NameHU: "Tőzsdén kívüli opció bróker tulajdonú elkülönített készpénzfedezete", Category: CategoryCollateral},
+	{Code: "OPSC", Name: "OTC option client owned cash segregated cash collateral", NameHU: "Tőzsdén kívüli opció ügyfél tulajdonú elkülönített készpénzfedezete", Category: CategoryCollateral},
+	{Code: "OPTN", Name: "FX option", NameHU: "Devizaopció", Category: CategoryTreasury},
+	{Code: "OTCD", Name: "OTC derivatives", NameHU: "Tőzsdén kívüli származtatott ügylet", Category: CategoryTreasury},
+	{Code: "OTHR", Name: "Other", NameHU: "Egyéb", Category: CategoryGeneral},
+	{Code: "OTLC", Name: "Other telecom related bill", NameHU: "Egyéb távközlési számla", Category: CategoryUtilities},
+	{Code: "PADD", Name: "Preauthorized debit", NameHU: "Előre engedélyezett terhelés", Category: CategoryGeneral},
+	{Code: "PAYR", Name: "Payroll", NameHU: "Bérszámfejtés", Category: CategorySalaryBenefits},
+	{Code: "PEFC", Name: "Pension fund contribution", NameHU: "Nyugdíjpénztári befizetés", Category: CategorySalaryBenefits},
+	{Code: "PENO", Name: "Payment based on enforcement order", NameHU: "Végrehajtási határozaton alapuló fizetés", Category: CategoryGeneral},
+	{Code: "PENS", Name: "Pension payment", NameHU: "Nyugdíj kifizetés", Category: CategorySalaryBenefits},
+	{Code: "PHON", Name: "Telephone bill", NameHU: "Telefonszámla", Category: CategoryUtilities},
+	{Code: "POPE", Name: "Point of purchase entry", NameHU: "Vásárlási ponton rögzített tétel", Category: CategoryCommercial},
+	{Code: "PPTI", Name: "Property insurance", NameHU: "Vagyonbiztosítás", Category: CategoryInsurance},
+	{Code: "PRCP", Name: "Price payment", NameHU: "Vételár fizetés", Category: CategoryCashManagement},
+	{Code: "PRME", Name: "Precious metal", NameHU: "Nemesfém", Category: CategoryTreasury},
+	{Code: "PTSP", Name: "Payment terms", NameHU: "Fizetési feltételek", Category: CategoryCommercial},
+	{Code: "PTXP", Name: "Property tax", NameHU: "Ingatlanadó", Category: CategoryTax},
+	{Code: "RAPI", Name: "Rapid payment instruction", NameHU: "Gyorsfizetési megbízás", Category: CategoryGeneral},
+	{Code: "RCKE", Name: "Represented check entry", NameHU: "Újra benyújtott csekk", Category: CategoryGeneral},
+	{Code: "RCPT", Name: "Receipt payment", NameHU: "Nyugta szerinti fizetés", Category: CategoryGeneral},
+	{Code: "RDTX", Name: "Road tax", NameHU: "Gépjárműadó", Category: CategoryTax},
+	{Code: "REBT", Name: "Rebate", NameHU: "Visszatérítés, engedmény", Category: CategoryCommercial},
+	{Code: "REFU", Name: "Refund", NameHU: "Visszatérítés", Category: CategoryGeneral},
+	{Code: "RELG", Name: "Rental lease general", NameHU: "Általános bérlet, lízing", Category: CategoryCommercial},
+	{Code: "RENT", Name: "Rent", NameHU: "Bérleti díj", Category: CategoryConsumer},
+	{Code: "REOD", Name: "Account overdraft repayment", NameHU: "Folyószámlahitel visszafizetése", Category: CategoryFinance},
+	{Code: "REPO", Name: "Repurchase agreement", NameHU: "Visszavásárlási megállapodás", Category: CategoryTreasury},
+	{Code: "RETL", Name: "Retail payment", NameHU: "Kiskereskedelmi fizetés", Category: CategoryConsumer},
+	{Code: "RHBS", Name: "Rehabilitation support", NameHU: "Rehabilitációs támogatás", Category: CategorySalaryBenefits},
+	{Code: "RIMB", Name: "Reimbursement of a previous erroneous transaction", NameHU: "Korábbi hibás tranzakció visszatérítése", Category: CategoryGeneral},
+	{Code: "RINP", Name: "Recurring installment payment", NameHU: "Rendszeres részletfizetés", Category: CategoryGeneral},
+	{Code: "RLWY", Name: "Railway", NameHU: "Vasút", Category: CategoryTransport},
+	{Code: "ROYA", Name: "Royalties", NameHU: "Jogdíjak", Category: CategoryCommercial},
+	{Code: "RPBC", Name: "Bilateral repo broker owned collateral", NameHU: "Kétoldalú repó bróker tulajdonú fedezete", Category: CategoryCollateral},
+	{Code: "RPCC", Name: "Repo client owned collateral", NameHU: "Repó ügyfél tulajdonú fedezete", Category: CategoryCollateral},
+	{Code: "RPNT", Name: "Bilateral repo internet netting", NameHU: "Kétoldalú repóügyletek nettósítása", Category: CategoryTreasury},
+	{Code: "RPSB", Name: "Bilateral repo broker owned segregated cash collateral", NameHU: "Kétoldalú repó bróker tulajdonú elkülönített készpénzfedezete", Category: CategoryCollateral},
+	{Code: "RPSC", Name: "Bilateral repo client owned segregated cash collateral", NameHU: "Kétoldalú repó ügyfél tulajdonú elkülönített készpénzfedezete", Category: CategoryCollateral},
+	{Code: "RRBN", Name: "Round robin", NameHU: "Körbeutalás", Category: CategoryTreasury},
+	{Code: "RRCT", Name: "Reimbursement received credit transfer", NameHU: "Visszatérítés jóváírt átutalásra", Category: CategoryGeneral},
+	{Code: "RRTP", Name: "Related request to pay", NameHU: "Kapcsolódó fizetési kérelem", Category: CategoryGeneral},
+	{Code: "RVPM", Name: "Receive against payment", NameHU: "Fizetés ellenében történő átvétel", Category: CategoryInvestment},
+	{Code: "SALA", Name: "Salary payment", NameHU: "Munkabér kifizetés", Category: CategorySalaryBenefits},
+	{Code: "SAVG", Name: "Savings", NameHU: "Megtakarítás", Category: CategoryFinance},
+	{Code: "SBSC", Name: "Securities buy sell sell buy back", NameHU: "Értékpapír adás-vétel visszavásárlással", Category: CategoryTreasury},
+	{Code: "SCIE", Name: "Single currency IRS exotic", NameHU: "Egzotikus egydevizás kamatcsere", Category: CategoryTreasury},
+	{Code: "SCIR", Name: "Single currency IRS", NameHU: "Egydevizás kamatcsere", Category: CategoryTreasury},
+	{Code: "SCRP", Name: "Securities cross products", NameHU: "Értékpapír termékek közötti ügylet", Category: CategoryTreasury},
+	{Code: "SCVE", Name: "Purchase sale of services", NameHU: "Szolgáltatás vásárlás", Category: CategoryCommercial},
+	{Code: "SECU", Name: "Securities", NameHU: "Értékpapírok", Category: CategoryInvestment},
+	{Code: "SEPI", Name: "Securities purchase in-house", NameHU: "Értékpapír-vásárlás, házon belül", Category: CategoryInvestment},
+	{Code: "SERV", Name: "Service charges", NameHU: "Szolgáltatási díjak", Category: CategoryCommercial},
+	{Code: "SHBC", Name: "Broker owned collateral short sale", NameHU: "Fedezetlen eladás bróker tulajdonú fedezete", Category: CategoryCollateral},
+	{Code: "SHCC", Name: "Client owned collateral short sale", NameHU: "Fedezetlen eladás ügyfél tulajdonú fedezete", Category: CategoryCollateral},
+	{Code: "SHSL", Name: "Short sell", NameHU: "Fedezetlen eladás", Category: CategoryTreasury},
+	{Code: "SLEB", Name: "Securities lending and borrowing", NameHU: "Értékpapír-kölcsönzés", Category: CategoryTreasury},
+	{Code: "SLOA", Name: "Secured loan", NameHU: "Fedezett kölcsön", Category: CategoryFinance},
+	{Code: "SLPI", Name: "Payment slip instruction", NameHU: "Készpénz-átutalási megbízás", Category: CategoryGeneral},
+	{Code: "SPLT", Name: "Split payments", NameHU: "Megosztott fizetés", Category: CategoryGeneral},
+	{Code: "SPSP", Name: "Salary pension sum payment", NameHU: "Bér és nyugdíj összevont kifizetése", Category: CategorySalaryBenefits},
+	{Code: "SSBE", Name: "Social security benefit", NameHU: "Társadalombiztosítási ellátás", Category: CategorySalaryBenefits},
+	{Code: "STDY", Name: "Study", NameHU: "Tanulmányok", Category: CategoryConsumer},
+	{Code: "SUBS", Name: "Subscription", NameHU: "Előfizetés", Category: CategoryCommercial},
+	{Code: "SUPP", Name: "Supplier payment", NameHU: "Szállítói kifizetés", Category: CategoryCommercial},
+	{Code: "SWBC", Name: "Swap broker owned cash collateral", NameHU: "Csereügylet bróker tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "SWCC", Name: "Swap client owned cash collateral", NameHU: "Csereügylet ügyfél tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "SWEP", Name: "Sweep", NameHU: "Számlasöprés", Category: CategoryCashManagement},
+	{Code: "SWFP", Name: "Swap contract final payment", NameHU: "Csereügylet záró fizetése", Category: CategoryTreasury},
+	{Code: "SWPP", Name: "Swap contract partial payment", NameHU: "Csereügylet részfizetése", Category: CategoryTreasury},
+	{Code: "SWPT", Name: "Swaption", NameHU: "Csereügyleti opció", Category: CategoryTreasury},
+	{Code: "SWRS", Name: "Swap contract reset payment", NameHU: "Csereügylet átárazási fizetése", Category: CategoryTreasury},
+	{Code: "SWSB", Name: "Swaps broker owned segregated cash collateral", NameHU: "Csereügylet bróker tulajdonú elkülönített készpénzfedezete", Category: CategoryCollateral},
+	{Code: "SWSC", Name: "Swaps client owned segregated cash collateral", NameHU: "Csereügylet ügyfél tulajdonú elkülönített készpénzfedezete", Category: CategoryCollateral},
+	{Code: "SWUF", Name: "Swap contract upfront payment", NameHU: "Csereügylet nyitó fizetése", Category: CategoryTreasury},
+	{Code: "TAXR", Name: "Tax refund", NameHU: "Adó-visszatérítés", Category: CategoryTax},
+	{Code: "TAXS", Name: "Tax payment", NameHU: "Adófizetés", Category: CategoryTax},
+	{Code: "TBAN", Name: "TBA pair-off netting", NameHU: "TBA ügyletek párosításának nettósítása", Category: CategoryTreasury},
+	{Code: "TBAS", Name: "To be announced", NameHU: "Később meghatározott értékpapír (TBA)", Category: CategoryTreasury},
+	{Code: "TBBC", Name: "TBA broker owned cash collateral", NameHU: "TBA ügylet bróker tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "TBCC", Name: "TBA client owned cash collateral", NameHU: "TBA ügylet ügyfél tulajdonú készpénzfedezete", Category: CategoryCollateral},
+	{Code: "TBIL", Name: "Telecommunications bill", NameHU: "Távközlési számla", Category: CategoryUtilities},
+	{Code: "TCSC", Name: "Town council service charges", NameHU: "Önkormányzati szolgáltatási díjak", Category: CategoryUtilities},
+	{Code: "TELI", Name: "Telephone initiated transaction", NameHU: "Telefonon kezdeményezett tranzakció", Category: CategoryGeneral},
+	{Code: "TLRF", Name: "Non-US mutual fund trailer fee payment", NameHU: "Nem amerikai befektetési alap jutalékfizetése", Category: CategoryInvestment},
+	{Code: "TLRR", Name: "Non-US mutual fund trailer fee rebate payment", NameHU: "Nem amerikai befektetési alap jutalék-visszatérítése", Category: CategoryInvestment},
+	{Code: "TMPG", Name: "TMPG claim payment", NameHU: "TMPG követelés kifizetése", Category: CategoryTreasury},
+	{Code: "TOLL", Name: "Toll", NameHU: "Útdíj", Category: CategoryTransport},
+	{Code: "TOPG", Name: "Topping", NameHU: "Számlafeltöltés", Category: CategoryCashManagement},
+	{Code: "TPRI", Name: "Tri-party repo interest", NameHU: "Háromoldalú repó kamata", Category: CategoryTreasury},
+	{Code: "TPRP", Name: "Tri-party repo netting", NameHU: "Háromoldalú repóügyletek nettósítása", Category: CategoryTreasury},
+	{Code: "TRAD", Name: "Trade services", NameHU: "Kereskedelmi szolgáltatások", Category: CategoryCommercial},
+	{Code: "TRCP", Name: "Treasury cross product", NameHU: "Treasury termékek közötti ügylet", Category: CategoryTreasury},
+	{Code: "TREA", Name: "Treasury payment", NameHU: "Treasury fizetés", Category: CategoryTreasury},
+	{Code: "TRFD", Name: "Trust fund", NameHU: "Vagyonkezelői alap", Category: CategoryFinance},
+	{Code: "TRNC", Name: "Truncated payment slip", NameHU: "Csonkított készpénz-átutalási megbízás", Category: CategoryGeneral},
+	{Code: "TRPT", Name: "Road pricing", NameHU: "Útdíjfizetés", Category: CategoryTransport},
+	{Code: "TRVC", Name: "Traveller cheque", NameHU: "Utazási csekk", Category: CategoryGeneral},
+	{Code: "UBIL", Name: "Utilities", NameHU: "Közüzemi díjak", Category: CategoryUtilities},
+	{Code: "UNIT", Name: "Unit trust purchase", NameHU: "Befektetési jegy vásárlás", Category: CategoryInvestment},
+	{Code: "VATX", Name: "Value added tax payment", NameHU: "ÁFA befizetés", Category: CategoryTax},
+	{Code: "VEHI", Name: "Vehicle associated payment", NameHU: "Járműhöz kapcsolódó fizetés", Category: CategoryTransport},
+	{Code: "VIEW", Name: "Vision care", NameHU: "Szemészeti ellátás", Category: CategoryMedical},
+	{Code: "WEBI", Name: "Internet initiated transaction", NameHU: "Interneten kezdeményezett tranzakció", Category: CategoryGeneral},
+	{Code: "WHLD", Name: "With holding", NameHU: "Forrásadó levonás", Category: CategoryTax},
+	{Code: "WTER", Name: "Water bill", NameHU: "Vízdíj", Category: CategoryUtilities},
+	{Code: "ZABA", Name: "Zero balancing", NameHU: "Nullszaldós egyenlegezés", Category: CategoryCashManagement},
+}
+
+var purposeIndex = func() map[string]PurposeCode {
+	index := make(map[string]PurposeCode, len(PurposeCodes))
+	for _, p := range PurposeCodes {
+		index[p.Code] = p
+	}
+	return index
+}()
+
+// LookupPurpose returns the catalogue entry of the code (case insensitive)
+func LookupPurpose(code string) (PurposeCode, bool) {
+	p, ok := purposeIndex[strings.ToUpper(code)]
+	return p, ok
+}
+
+// SearchPurposes returns the entries where the code, the names or the category contains the keyword (case insensitive)
+// An empty keyword returns the full catalogue.
+func SearchPurposes(keyword string) []PurposeCode {
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+
+	var found []PurposeCode
+	for _, p := range PurposeCodes {
+		if strings.Contains(strings.ToLower(p.Code), keyword) ||
+			strings.Contains(strings.ToLower(p.Name), keyword) ||
+			strings.Contains(strings.ToLower(p.NameHU), keyword) ||
+			strings.Contains(strings.ToLower(p.Category), keyword) {
+			found = append(found, p)
+		}
+	}
+	return found
+}
+
+// Purpose for the transaction, it should be a code from the PurposeCodes catalogue
+func (c *Code) Purpose(purpose string) error {
+	if len(purpose) != 4 {
+		return errors.New("purpose has invalid length")
+	}
+
+	p, ok := LookupPurpose(purpose)
+	if !ok {
+		return errors.New("invalid purpose code")
+	}
+
+	c.purpose = p.Code
+	return nil
+}
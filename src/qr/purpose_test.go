@@ -0,0 +1,64 @@
+package qr
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurposeCatalogue(t *testing.T) {
+	assert.True(t, sort.SliceIsSorted(PurposeCodes, func(i, j int) bool { return PurposeCodes[i].Code < PurposeCodes[j].Code }))
+	assert.Len(t, purposeIndex, len(PurposeCodes), "no duplicates")
+
+	for _, p := range PurposeCodes {
+		assert.Len(t, p.Code, 4)
+		assert.NotEmpty(t, p.Name, p.Code)
+		assert.NotEmpty(t, p.NameHU, p.Code)
+		assert.NotEmpty(t, p.Category, p.Code)
+	}
+}
+
+func TestLookupPurpose(t *testing.T) {
+	p, ok := LookupPurpose("gdsv")
+	assert.True(t, ok)
+	assert.Equal(t, "GDSV", p.Code)
+	assert.Equal(t, CategoryCommercial, p.Category)
+
+	p, ok = LookupPurpose("MGCC")
+	assert.True(t, ok)
+	assert.Equal(t, CategoryCollateral, p.Category)
+
+	for _, code := range []string{"ADCS", "CPKC", "EPAY", "INTP", "RELG", "TAXR", "TOLL", "VEHI"} {
+		_, ok = LookupPurpose(code)
+		assert.True(t, ok, code)
+	}
+
+	_, ok = LookupPurpose("XXXX")
+	assert.False(t, ok)
+}
+
+func TestSearchPurposes(t *testing.T) {
+	assert.Len(t, SearchPurposes(""), len(PurposeCodes))
+
+	found := SearchPurposes("bill")
+	assert.NotEmpty(t, found)
+	for _, p := range found {
+		assert.Contains(t, p.Name, "bill")
+	}
+
+	// Hungarian and the category
+	found = SearchPurposes("VILLANY")
+	assert.Len(t, found, 1)
+	assert.Equal(t, "ELEC", found[0].Code)
+	assert.Len(t, SearchPurposes("dividend"), 1)
+	assert.Len(t, SearchPurposes("mgmt"), 14)
+
+	assert.Empty(t, SearchPurposes("nothing like this"))
+}
+
+func TestPurposeLowerCase(t *testing.T) {
+	c := &Code{}
+	assert.NoError(t, c.Purpose("acct"))
+	assert.Equal(t, "ACCT", c.purpose)
+}
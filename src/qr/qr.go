@@ -1,12 +1,11 @@
 package qr
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
-
-	"github.com/skip2/go-qrcode"
 )
 
 type Code struct {
@@ -40,9 +39,6 @@ var (
 
 	// KindRTP for request money
 	KindRTP kind = "RTP"
-
-	// See the details in the checks, not all codes here maybe
-	purposeCodes = []string{"ACCT", "ADVA", "AGRT", "AIRB", "ALMY", "ANNI", "ANTS", "AREN", "BECH", "BENE", "BEXP", "BOCE", "BONU", "BUSB", "CASH", "CBFF", "CBTV", "CCRD", "CDBL", "CFEE", "CHAR", "CLPR", "CMDT", "COLL", "COMC", "COMM", "COMT", "COST", "CPYR", "CSDB", "CSLP", "CVCF", "DBTC", "DCRD", "DEPT", "DERI", "DIVD", "DMEQ", "DNTS", "ELEC", "ENRG", "ESTX", "FERB", "FREX", "GASB", "GDDS", "GDSV", "GOVI", "GOVT", "GSCB", "GVEA", "GVEB", "GVEC", "GVED", "HEDG", "HLRP", "HLTC", "HLTI", "HREC", "HSPC", "HSTX", "ICCP", "ICRF", "IDCP", "IHRP", "INPC", "INSM", "INSU", "INTC", "INTE", "INTX", "LBRI", "LICF", "LIFI", "LIMA", "LOAN", "LOAR", "LTCF", "MDCS", "MSVC", "NETT", "NITX", "NOWS", "NWCH", "NWCM", "OFEE", "OTHR", "OTLC", "PADD", "PAYR", "PENS", "PHON", "POPE", "PPTI", "PRCP", "PRME", "PTSP", "RCKE", "RCPT", "REFU", "RENT", "RINP", "RLWY", "ROYA", "SALA", "SAVG", "SCVE", "SECU", "SSBE", "STDY", "SUBS", "SUPP", "TAXS", "TELI", "TRAD", "TREA", "TRFD", "VATX", "VIEW", "WEBI", "WHLD", "WTER"}
 )
 
 // kind QR Code type
@@ -64,129 +60,73 @@ func (v version) String() string {
 	return string(v)
 }
 
-// Amount for payment (optional)
-type amount struct {
-	currency string
-	total    int
-}
-
-// String .
-func (a amount) String() string {
-	currency := a.currency
-	if currency == "" {
-		currency = "HUF"
-	}
-	return fmt.Sprintf("%s%d", currency, a.total)
-}
-
+// GeneratePNG returns the code as a PNG image
 func (c Code) GeneratePNG(size int) ([]byte, error) {
-	if c.Valid.Expired() {
-		return nil, errors.New("negative validity period")
-	}
-
-	qrContent := c.String()
-	if len(qrContent) > qrContentMaxSize {
-		return nil, errors.New("qr content is too large")
-	}
-
-	q, err := qrcode.New(qrContent, qrcode.Medium) // We should never hit this part
+	var buf bytes.Buffer
+	err := c.WritePNG(&buf, RenderOptions{Size: size})
 	if err != nil {
 		return nil, err
 	}
-
-	if q.VersionNumber > 13 { // This part should be unreachable
-		return nil, errors.New("generated image (version) is too high (content too big)")
-	}
-	return q.PNG(size)
+	return buf.Bytes(), nil
 }
 
 // String .
 func (c Code) String() string {
 	var sb strings.Builder
+	_, _ = c.WriteTo(&sb)
+	return sb.String()
+}
 
-	sb.WriteString(c.Kind.String())
-	sb.WriteString("\n")
-
-	sb.WriteString(c.Version.String())
-	sb.WriteString("\n")
-
-	if c.Charset == 0 {
-		sb.WriteString("1") // Set default to 1
-	} else {
-		sb.WriteString(fmt.Sprintf("%d", c.Charset))
-	}
-	sb.WriteString("\n")
-
-	sb.WriteString(c.BIC)
-	sb.WriteString("\n")
-
-	sb.WriteString(c.Name)
-	sb.WriteString("\n")
-
-	sb.WriteString(c.IBAN)
-	sb.WriteString("\n")
-
-	// Optional amount
-	if c.Amount.total > 0 {
-		sb.WriteString(c.Amount.String())
-	}
-	sb.WriteString("\n")
-
-	if time.Time(c.Valid).IsZero() {
-		// TODO: Add test for this part
-		// Add a default time with one hour expire
-		sb.WriteString(date(time.Now().Add(time.Hour)).String())
-	} else {
-		sb.WriteString(c.Valid.String())
-	}
-	sb.WriteString("\n")
-
-	if c.purpose != "" {
-		sb.WriteString(c.purpose)
-	}
-	sb.WriteString("\n")
-
-	if c.message != "" {
-		sb.WriteString(c.message)
-	}
-	sb.WriteString("\n")
-
-	if c.shopID != "" {
-		sb.WriteString(c.shopID)
+// payloadLines splits an MNB payload into lines, the empty lines after the last field are dropped
+// Those are the new lines closing the payload, e.g. the one added by fmt.Println to the String output.
+func payloadLines(content string) []string {
+	lines := strings.Split(content, "\n")
+	for len(lines) > len(Fields) && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
 	}
-	sb.WriteString("\n")
+	return lines
+}
 
-	if c.merchDevID != "" {
-		sb.WriteString(c.merchDevID)
+// Parse a payload generated by String back to a Code
+func Parse(content string) (*Code, error) {
+	lines := payloadLines(content)
+	if len(lines) != len(Fields) {
+		return nil, fmt.Errorf("invalid line count: %d", len(lines))
 	}
-	sb.WriteString("\n")
 
-	if c.invoiceID != "" {
-		sb.WriteString(c.invoiceID)
-	}
-	sb.WriteString("\n")
+	c := &Code{}
+	for _, f := range Fields {
+		value := lines[f.Line]
+		if value == "" {
+			if f.Required {
+				return nil, fmt.Errorf("%s is required", f.Name)
+			}
+			continue
+		}
 
-	if c.customerID != "" {
-		sb.WriteString(c.customerID)
+		if err := f.set(c, value); err != nil {
+			return nil, err
+		}
 	}
-	sb.WriteString("\n")
+	return c, nil
+}
 
-	if c.credTranID != "" {
-		sb.WriteString(c.credTranID)
+// Validate the required fields and the expiration
+func (c Code) Validate() error {
+	if c.Kind != KindHCT && c.Kind != KindRTP {
+		return errors.New("invalid kind")
 	}
-	sb.WriteString("\n")
 
-	if c.loyaltyID != "" {
-		sb.WriteString(c.loyaltyID)
+	for _, f := range Fields {
+		if f.Required && f.get(&c) == "" {
+			return fmt.Errorf("%s is required", f.Name)
+		}
 	}
-	sb.WriteString("\n")
 
-	if c.navCheckID != "" {
-		sb.WriteString(c.navCheckID)
+	if c.Valid.Expired() {
+		return errors.New("negative validity period")
 	}
-	sb.WriteString("\n")
-
-	return sb.String()
+	return nil
 }
 
 // HUFAmount for the transaction
@@ -215,100 +155,49 @@ func (c *Code) ValidUntil(t time.Time) error {
 	return nil
 }
 
-// Purpose for the transaction
-// Possible values are the AT-44 codes: https://www.rba.hr/documents/20182/183267/External+purpose+codes+list/8a28f888-1f83-5e29-d6ed-fce05f428689?version=1.1
-func (c *Code) Purpose(purpose string) error {
-	if len(purpose) != 4 {
-		return errors.New("purpose has invalid length")
-	}
-	purpose = strings.ToUpper(purpose)
-
-	found := false
-	for _, c := range purposeCodes {
-		if purpose == c {
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		return errors.New("invalid purpose code")
-	}
-
-	c.purpose = purpose
-	return nil
+// Expiry returns the validity timestamp, zero if it's not set
+func (c Code) Expiry() time.Time {
+	return time.Time(c.Valid)
 }
 
 // Message .
 func (c *Code) Message(msg string) error {
-	if len(msg) > 70 {
-		return errors.New("message is too long")
-	}
-	c.message = msg
-	return nil
+	return c.Set("message", msg)
 }
 
 // ShopID .
 func (c *Code) ShopID(shopID string) error {
-	if len(shopID) > 35 {
-		return errors.New("shopID is too long")
-	}
-	c.shopID = shopID
-	return nil
+	return c.Set("shopID", shopID)
 }
 
 // MerchDevID .
 func (c *Code) MerchDevID(merchDevID string) error {
-	if len(merchDevID) > 35 {
-		return errors.New("merchDevID is too long")
-	}
-	c.merchDevID = merchDevID
-	return nil
+	return c.Set("merchDevID", merchDevID)
 }
 
 // InvoiceID .
 func (c *Code) InvoiceID(invoiceID string) error {
-	if len(invoiceID) > 35 {
-		return errors.New("invoiceID is too long")
-	}
-	c.invoiceID = invoiceID
-	return nil
+	return c.Set("invoiceID", invoiceID)
 }
 
 // CustomerID .
 func (c *Code) CustomerID(customerID string) error {
-	if len(customerID) > 35 {
-		return errors.New("customerID is too long")
-	}
-	c.customerID = customerID
-	return nil
+	return c.Set("customerID", customerID)
 }
 
 // CredTranID .
 func (c *Code) CredTranID(credTranID string) error {
-	if len(credTranID) > 35 {
-		return errors.New("credTranID is too long")
-	}
-	c.credTranID = credTranID
-	return nil
+	return c.Set("credTranID", credTranID)
 }
 
 // LoyaltyID .
 func (c *Code) LoyaltyID(loyaltyID string) error {
-	if len(loyaltyID) > 35 {
-		return errors.New("loyaltyID is too long")
-	}
-	c.loyaltyID = loyaltyID
-	return nil
+	return c.Set("loyaltyID", loyaltyID)
 }
 
-// NavCheckID .
+// NavCheckID sets the NAV check identifier, only its length is validated
 func (c *Code) NavCheckID(navCheckID string) error {
-	if len(navCheckID) > 35 {
-		return errors.New("navCheckID is too long")
-	}
-	c.navCheckID = navCheckID
-	return nil
+	return c.Set("navCheckID", navCheckID)
 }
 
 // NewPaymentSend QR code creation
@@ -346,23 +235,13 @@ func NewPaymentRequest(bic string, name string, iban string) (*Code, error) {
 }
 
 func addRecipient(code *Code, bic, name, iban string) error {
-	if len(bic) == 8 {
-		bic = bic + "XXX" // For SEPA payment the 8 char long SWIFT should be extended with XXX to 11 chars
+	if err := setBIC(code, bic); err != nil {
+		return err
 	}
 
-	if len(bic) != 11 {
-		return errors.New("invalid BIC length")
+	if err := setName(code, name); err != nil {
+		return err
 	}
-	code.BIC = bic
 
-	if len(name) > 70 {
-		return errors.New("name should not be longer than 70")
-	}
-	code.Name = name
-
-	if len(iban) != 28 {
-		return errors.New("invalid IBAN length")
-	}
-	code.IBAN = iban
-	return nil
+	return setIBAN(code, iban)
 }
@@ -167,7 +167,7 @@ func TestGeneratePNG(t *testing.T) {
 	// Fill all the fields and gen again and try to hit the version error
 	c = genFullCode(t)
 	_, err = c.GeneratePNG(64)
-	assert.Equal(t, "qr content is too large", err.Error())
+	assert.Equal(t, "qr content is too large: 483 bytes, maximum is 345", err.Error())
 
 	// Test the max size (generated size with full content: 483, so remove some fields here
 	c.navCheckID = ""   // -35
@@ -207,6 +207,59 @@ func TestFullCode(t *testing.T) {
 	assert.Equal(t, 483, len(genStr)) // The fully packed code's length, but the standard won't allow that
 }
 
+func TestCodeFormatDefaultValid(t *testing.T) {
+	c, err := NewPaymentSend("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(t, err)
+
+	output := strings.Split(c.String(), "\n")
+	d, err := parseDate(output[7])
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), time.Time(d), 2*time.Second)
+}
+
+func TestParse(t *testing.T) {
+	c := genFullCode(t)
+
+	parsed, err := Parse(c.String())
+	assert.NoError(t, err)
+	assert.Equal(t, c.String(), parsed.String())
+
+	// Without the last new line
+	parsed, err = Parse(strings.TrimSuffix(c.String(), "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, c.String(), parsed.String())
+
+	// Printed by the generator with fmt.Println and saved with an extra new line
+	parsed, err = Parse(c.String() + "\n\n")
+	assert.NoError(t, err)
+	assert.Equal(t, c.String(), parsed.String())
+
+	// Minimal code
+	c, err = NewPaymentRequest("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(t, err)
+	parsed, err = Parse(c.String())
+	assert.NoError(t, err)
+	assert.Equal(t, KindRTP, parsed.Kind)
+	assert.Equal(t, 0, parsed.Amount.total)
+	assert.Equal(t, "", parsed.message)
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse("HCT\n001\n")
+	assert.EqualError(t, err, "invalid line count: 3")
+
+	c := genFullCode(t)
+	lines := strings.Split(c.String(), "\n")
+	lines[4] = ""
+	_, err = Parse(strings.Join(lines, "\n"))
+	assert.EqualError(t, err, "name is required")
+
+	lines = strings.Split(c.String(), "\n")
+	lines[8] = "XXXX"
+	_, err = Parse(strings.Join(lines, "\n"))
+	assert.EqualError(t, err, "invalid purpose code")
+}
+
 func genFullCode(t *testing.T) *Code {
 	c, err := NewPaymentSend("abcdefgh", strings.Repeat("a", 70), "HU00123456789012345678901234")
 	assert.NoError(t, err)
@@ -226,3 +279,11 @@ func genFullCode(t *testing.T) *Code {
 	assert.NoError(t, c.NavCheckID(strings.Repeat("i", 35)))
 	return c
 }
+
+func TestExpiry(t *testing.T) {
+	c := Code{}
+	assert.True(t, c.Expiry().IsZero())
+
+	assert.NoError(t, c.Set("valid", "20240102150405+1"))
+	assert.Equal(t, "2024-01-02T15:04:05+01:00", c.Expiry().Format(time.RFC3339))
+}
@@ -0,0 +1,202 @@
+package qr
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"sync"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// RenderOptions for the image generation
+type RenderOptions struct {
+	Size     int             // Image size in pixels (PNG) or the display size (SVG)
+	Capacity CapacityOptions // Limits of the code, DefaultCapacityOptions if not set
+	Verify   bool            // Decode the rendered image and compare it with the payload before writing it
+}
+
+func (o RenderOptions) capacity(defaults CapacityOptions) CapacityOptions {
+	if o.Capacity == (CapacityOptions{}) {
+		return defaults
+	}
+	return o.Capacity
+}
+
+// pngBuffers reuses the PNG encoder buffers between the renders
+type pngBuffers struct {
+	pool sync.Pool
+}
+
+// Get .
+func (p *pngBuffers) Get() *png.EncoderBuffer {
+	b, _ := p.pool.Get().(*png.EncoderBuffer)
+	return b
+}
+
+// Put .
+func (p *pngBuffers) Put(b *png.EncoderBuffer) {
+	p.pool.Put(b)
+}
+
+var pngEncoder = png.Encoder{
+	CompressionLevel: png.BestCompression,
+	BufferPool:       &pngBuffers{},
+}
+
+// pngImages reuses the images of the renders, every pixel is redrawn
+var pngImages sync.Pool
+
+var pngPalette = color.Palette{color.White, color.Black}
+
+// bitmapImage draws the bitmap like qrcode.Image does, the image should be put back to pngImages after the use
+// The smaller sizes are enlarged to one pixel per module.
+func bitmapImage(bitmap [][]bool, size int) *image.Paletted {
+	modules := len(bitmap)
+	if size < modules {
+		size = modules
+	}
+
+	img, _ := pngImages.Get().(*image.Paletted)
+	if img == nil || img.Rect.Dx() != size {
+		img = image.NewPaletted(image.Rect(0, 0, size, size), pngPalette)
+	}
+
+	modulesPerPixel := float64(modules) / float64(size)
+	for y := 0; y < size; y++ {
+		row := bitmap[int(float64(y)*modulesPerPixel)]
+		pix := img.Pix[y*img.Stride : y*img.Stride+size]
+		for x := range pix {
+			pix[x] = 0
+			if row[int(float64(x)*modulesPerPixel)] {
+				pix[x] = 1
+			}
+		}
+	}
+	return img
+}
+
+// WriteTo writes the text payload, the same as String
+func (c Code) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, f := range Fields {
+		n, err := io.WriteString(w, f.get(&c))
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+
+		n, err = io.WriteString(w, "\n")
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// WritePNG renders the code as a PNG image into the writer
+// Nothing is written if the code could not be generated.
+func (c Code) WritePNG(w io.Writer, opts RenderOptions) error {
+	q, err := c.qrCode(opts)
+	if err != nil {
+		return err
+	}
+
+	return writePNG(w, q, opts, readBack{text: q.Content, charset: charsetUTF8})
+}
+
+// WriteSVG renders the code as an SVG image into the writer
+// Nothing is written if the code could not be generated.
+func (c Code) WriteSVG(w io.Writer, opts RenderOptions) error {
+	q, err := c.qrCode(opts)
+	if err != nil {
+		return err
+	}
+
+	return writeVerifiedSVG(w, q, opts, readBack{text: q.Content, charset: charsetUTF8})
+}
+
+// WriteTerminal renders the code with unicode block characters for terminals
+func (c Code) WriteTerminal(w io.Writer, opts RenderOptions) error {
+	q, err := c.qrCode(opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, q.ToSmallString(false))
+	return err
+}
+
+func (c Code) qrCode(opts RenderOptions) (*qrcode.QRCode, error) {
+	if c.Valid.Expired() {
+		return nil, errors.New("negative validity period")
+	}
+
+	return encode(c.String(), opts.capacity(DefaultCapacityOptions))
+}
+
+// writePNG encodes the code and renders the image once, the same image is verified and written
+func writePNG(w io.Writer, q *qrcode.QRCode, opts RenderOptions, expected readBack) error {
+	bitmap := q.Bitmap()
+	if opts.Verify {
+		if err := checkModuleSize(len(bitmap), opts.Size); err != nil {
+			return err
+		}
+	}
+
+	img := bitmapImage(bitmap, opts.Size)
+	defer pngImages.Put(img)
+	if opts.Verify {
+		if err := verifyImage(img, expected); err != nil {
+			return err
+		}
+	}
+	return pngEncoder.Encode(w, img)
+}
+
+// writeVerifiedSVG writes the SVG, the verification uses the display size or the smallest accepted size without it
+func writeVerifiedSVG(w io.Writer, q *qrcode.QRCode, opts RenderOptions, expected readBack) error {
+	bitmap := q.Bitmap()
+	if opts.Verify {
+		size := opts.Size
+		if size <= 0 {
+			size = len(bitmap) * minModulePixels
+		}
+		if err := verify(bitmap, size, expected); err != nil {
+			return err
+		}
+	}
+	return writeSVG(w, bitmap, opts.Size)
+}
+
+// writeSVG writes the bitmap (with the quiet zone) as a single path, one horizontal run per rectangle
+func writeSVG(w io.Writer, bitmap [][]bool, size int) error {
+	modules := len(bitmap)
+	if size <= 0 {
+		size = modules
+	}
+
+	bw := bufio.NewWriter(w)
+	_, _ = fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, size, size, modules, modules)
+	_, _ = fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, modules, modules)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); x++ {
+			if !row[x] {
+				continue
+			}
+
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			_, _ = fmt.Fprintf(bw, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	_, _ = bw.WriteString(`"/></svg>`)
+	return bw.Flush()
+}
@@ -0,0 +1,171 @@
+package qr
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func genValidCode(tb testing.TB) *Code {
+	c, err := NewPaymentSend("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(tb, err)
+	assert.NoError(tb, c.HUFAmount(500))
+	assert.NoError(tb, c.Message("hello!"))
+	assert.NoError(tb, c.ValidUntil(time.Now().Add(time.Hour)))
+	return c
+}
+
+func TestWriteTo(t *testing.T) {
+	c := genFullCode(t)
+
+	var buf bytes.Buffer
+	n, err := c.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(483), n)
+	assert.Equal(t, c.String(), buf.String())
+
+	_, err = c.WriteTo(failWriter{})
+	assert.EqualError(t, err, "write failed")
+}
+
+func TestWritePNG(t *testing.T) {
+	c := genValidCode(t)
+
+	var buf bytes.Buffer
+	assert.NoError(t, c.WritePNG(&buf, RenderOptions{Size: 128}))
+
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 128, img.Bounds().Dx())
+
+	expected, err := c.GeneratePNG(128)
+	assert.NoError(t, err)
+	buf.Reset()
+	assert.NoError(t, c.WritePNG(&buf, RenderOptions{Size: 128}))
+	assert.Equal(t, expected, buf.Bytes())
+}
+
+func TestWritePNGErrors(t *testing.T) {
+	c, err := NewPaymentSend("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.EqualError(t, c.WritePNG(&buf, RenderOptions{Size: 128}), "negative validity period")
+	assert.Equal(t, 0, buf.Len())
+
+	c = genFullCode(t)
+	assert.EqualError(t, c.WritePNG(&buf, RenderOptions{Size: 128}), "qr content is too large: 483 bytes, maximum is 345")
+	assert.Equal(t, 0, buf.Len())
+
+	// Custom limits
+	opts := RenderOptions{Size: 128, Capacity: DefaultCapacityOptions}
+	opts.Capacity.MaxSize = 500
+	opts.Capacity.MaxVersion = 40
+	assert.NoError(t, c.WritePNG(&buf, opts))
+}
+
+func TestWriteSVG(t *testing.T) {
+	c := genValidCode(t)
+
+	var buf bytes.Buffer
+	assert.NoError(t, c.WriteSVG(&buf, RenderOptions{Size: 256}))
+
+	svg := buf.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="256" height="256"`))
+	assert.True(t, strings.HasSuffix(svg, `"/></svg>`))
+	assert.Contains(t, svg, `<path fill="#000" d="M`)
+
+	assert.EqualError(t, c.WriteSVG(failWriter{}, RenderOptions{Size: 256}), "write failed")
+}
+
+func TestWriteSVGBitmap(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeSVG(&buf, [][]bool{{true, true, false}, {false, false, true}, {false, false, false}}, 0))
+	assert.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="3" height="3" viewBox="0 0 3 3" shape-rendering="crispEdges">`+
+		`<rect width="3" height="3" fill="#fff"/><path fill="#000" d="M0 0h2v1h-2zM2 1h1v1h-1z"/></svg>`, buf.String())
+}
+
+func TestBitmapImage(t *testing.T) {
+	for _, content := range []string{"HCT\n001\n", genValidCode(t).String()} {
+		q, err := encode(content, DefaultCapacityOptions)
+		assert.NoError(t, err)
+		bitmap := q.Bitmap()
+
+		// The reused images are redrawn fully
+		for _, size := range []int{10, 100, 128, 100, 256} {
+			img := bitmapImage(bitmap, size)
+			assert.Equal(t, q.Image(size).(*image.Paletted).Pix, img.Pix, size)
+			pngImages.Put(img)
+		}
+	}
+}
+
+func TestWriteTerminal(t *testing.T) {
+	c := genValidCode(t)
+
+	var buf bytes.Buffer
+	assert.NoError(t, c.WriteTerminal(&buf, RenderOptions{}))
+	assert.Contains(t, buf.String(), "█")
+}
+
+func BenchmarkString(b *testing.B) {
+	c := genValidCode(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = ioutil.Discard.Write([]byte(c.String()))
+	}
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	c := genValidCode(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = c.WriteTo(ioutil.Discard)
+	}
+}
+
+// The allocations of the PNG renders are made by the QR encoder, the writer saves the image and the output copies only
+func BenchmarkGeneratePNG(b *testing.B) {
+	c := genValidCode(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		png, _ := c.GeneratePNG(256)
+		_, _ = ioutil.Discard.Write(png)
+	}
+}
+
+func BenchmarkWritePNG(b *testing.B) {
+	c := genValidCode(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = c.WritePNG(ioutil.Discard, RenderOptions{Size: 256})
+	}
+}
+
+// BenchmarkQRCodePNG is the same render with a new image from the encoder library, for comparing with BenchmarkWritePNG
+func BenchmarkQRCodePNG(b *testing.B) {
+	c := genValidCode(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		q, _ := encode(c.String(), DefaultCapacityOptions)
+		_ = pngEncoder.Encode(ioutil.Discard, q.Image(256))
+	}
+}
@@ -0,0 +1,128 @@
+package qr
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	rfPrefix    = "RF"
+	rfMaxLength = 25 // RF, two check digits and at most 21 characters
+)
+
+// NewCreditorReference creates an ISO 11649 (RF) creditor reference from a reference of at most 21 letters or digits
+// The reference is converted to upper case, the spaces are removed.
+func NewCreditorReference(reference string) (string, error) {
+	reference = strings.ToUpper(strings.ReplaceAll(reference, " ", ""))
+	if len(reference) == 0 || len(reference) > rfMaxLength-4 {
+		return "", errors.New("invalid creditor reference length")
+	}
+
+	if !alphanumeric(reference) {
+		return "", errors.New("invalid creditor reference character")
+	}
+
+	check := 98 - mod97(reference+rfPrefix+"00")
+	return rfPrefix + strconv.Itoa(check/10) + strconv.Itoa(check%10) + reference, nil
+}
+
+// ValidateCreditorReference checks the structure and the mod-97 check digits of an RF creditor reference
+// Both the electronic (RF18539007547034) and the print format (RF18 5390 0754 7034) are accepted.
+func ValidateCreditorReference(ref string) error {
+	ref = strings.ReplaceAll(ref, " ", "")
+	if len(ref) < 5 || len(ref) > rfMaxLength {
+		return errors.New("invalid creditor reference length")
+	}
+
+	if !strings.HasPrefix(ref, rfPrefix) || !IsDigits(ref[2:4]) {
+		return errors.New("invalid creditor reference prefix")
+	}
+
+	if !alphanumeric(ref[4:]) {
+		return errors.New("invalid creditor reference character")
+	}
+
+	if mod97(ref[4:]+ref[:4]) != 1 {
+		return errors.New("invalid creditor reference checksum")
+	}
+	return nil
+}
+
+// FindCreditorReference returns the first valid RF creditor reference of a free text in electronic format
+// The reference could be in print format too, empty string is returned without a valid reference.
+func FindCreditorReference(text string) string {
+	text = strings.ToUpper(text)
+	for i := strings.Index(text, rfPrefix); i >= 0; i = nextRFPrefix(text, i) {
+		if i > 0 && isAlphanumeric(rune(text[i-1])) {
+			continue // Part of a word
+		}
+
+		// The longest valid reference, the print format has groups of four characters, the last could be shorter
+		var found, candidate string
+		for j, group := range strings.Fields(text[i:]) {
+			end := strings.IndexFunc(group, func(r rune) bool { return !isAlphanumeric(r) })
+			if end >= 0 {
+				group = group[:end]
+			}
+
+			if j > 0 && len(group) > 4 {
+				break
+			}
+
+			candidate += group
+			if len(candidate) > rfMaxLength {
+				break
+			}
+			if ValidateCreditorReference(candidate) == nil {
+				found = candidate
+			}
+			if end >= 0 || len(group) != 4 {
+				break // Punctuation or a short group ends the reference
+			}
+		}
+
+		if found != "" {
+			return found
+		}
+	}
+	return ""
+}
+
+// CreditorReference sets a valid RF creditor reference as the invoiceID of the code, in electronic format
+func (c *Code) CreditorReference(ref string) error {
+	if err := ValidateCreditorReference(ref); err != nil {
+		return err
+	}
+	return c.InvoiceID(strings.ReplaceAll(ref, " ", ""))
+}
+
+// FindCreditorReference returns the RF creditor reference of the invoiceID or the message, empty without any
+func (c Code) FindCreditorReference() string {
+	if ref := FindCreditorReference(c.invoiceID); ref != "" {
+		return ref
+	}
+	return FindCreditorReference(c.message)
+}
+
+// nextRFPrefix returns the index of the RF prefix after the one at i, -1 without more
+func nextRFPrefix(text string, i int) int {
+	j := strings.Index(text[i+len(rfPrefix):], rfPrefix)
+	if j < 0 {
+		return -1
+	}
+	return i + len(rfPrefix) + j
+}
+
+func alphanumeric(s string) bool {
+	for _, r := range s {
+		if !isAlphanumeric(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphanumeric(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,77 @@
+package qr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCreditorReference(t *testing.T) {
+	ref, err := NewCreditorReference("5390 0754 7034")
+	assert.NoError(t, err)
+	assert.Equal(t, "RF18539007547034", ref)
+
+	ref, err = NewCreditorReference("inv2024001")
+	assert.NoError(t, err)
+	assert.Equal(t, "RF78INV2024001", ref)
+
+	ref, err = NewCreditorReference("1")
+	assert.NoError(t, err)
+	assert.Equal(t, "RF741", ref)
+
+	_, err = NewCreditorReference("")
+	assert.EqualError(t, err, "invalid creditor reference length")
+
+	_, err = NewCreditorReference("1234567890123456789012")
+	assert.EqualError(t, err, "invalid creditor reference length")
+
+	_, err = NewCreditorReference("INV-2024")
+	assert.EqualError(t, err, "invalid creditor reference character")
+}
+
+func TestValidateCreditorReference(t *testing.T) {
+	assert.NoError(t, ValidateCreditorReference("RF18539007547034"))
+	assert.NoError(t, ValidateCreditorReference("RF18 5390 0754 7034"))
+	assert.NoError(t, ValidateCreditorReference("RF741"))
+
+	for ref, msg := range map[string]string{
+		"RF18":                        "invalid creditor reference length",
+		"RF1853900754703412345678901": "invalid creditor reference length",
+		"XX18539007547034":            "invalid creditor reference prefix",
+		"RFAB539007547034":            "invalid creditor reference prefix",
+		"RF18539007547-34":            "invalid creditor reference character",
+		"RF19539007547034":            "invalid creditor reference checksum",
+	} {
+		assert.EqualError(t, ValidateCreditorReference(ref), msg, ref)
+	}
+}
+
+func TestFindCreditorReference(t *testing.T) {
+	for text, ref := range map[string]string{
+		"RF18539007547034":                    "RF18539007547034",
+		"Payment rf18539007547034, thanks":    "RF18539007547034",
+		"Ref: RF18 5390 0754 7034 paid":       "RF18539007547034",
+		"SURF18539007547034 RF78INV2024001":   "RF78INV2024001",
+		"RF19539007547034":                    "",
+		"No reference":                        "",
+		"RF18 5390 0754 7034\nRF78INV2024001": "RF18539007547034",
+		"Invoice RF741.":                      "RF741",
+	} {
+		assert.Equal(t, ref, FindCreditorReference(text), text)
+	}
+}
+
+func TestCodeCreditorReference(t *testing.T) {
+	c, err := NewPaymentSend("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(t, err)
+	assert.Equal(t, "", c.FindCreditorReference())
+
+	assert.EqualError(t, c.CreditorReference("RF19539007547034"), "invalid creditor reference checksum")
+	assert.NoError(t, c.CreditorReference("RF18 5390 0754 7034"))
+	assert.Equal(t, "RF18539007547034", c.Get("invoiceID"))
+	assert.Equal(t, "RF18539007547034", c.FindCreditorReference())
+
+	assert.NoError(t, c.InvoiceID("INV-1"))
+	assert.NoError(t, c.Message("Számla RF78INV2024001"))
+	assert.Equal(t, "RF78INV2024001", c.FindCreditorReference())
+}
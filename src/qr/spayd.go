@@ -0,0 +1,350 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	spaydHeader    = "SPD*1.0*"
+	spaydMaxAmount = 999999999 // 9999999.99 in hundredths
+	spaydDate      = "20060102"
+)
+
+var (
+	// KindSPAYD for the Czech Short Payment Descriptor, the payload itself starts with the SPD header
+	KindSPAYD kind = "SPAYD"
+
+	// SPAYDCapacityOptions used for the SPAYD codes
+	SPAYDCapacityOptions = CapacityOptions{
+		Level:      qrcode.Medium,
+		MaxVersion: 13,
+		MaxSize:    qrContentMaxSize,
+		Truncate:   TruncateNone,
+	}
+)
+
+// SPAYDCode is a Czech Short Payment Descriptor (QR Platba)
+// Standard: https://qr-platba.cz/pro-vyvojare/specifikace-formatu/
+type SPAYDCode struct {
+	IBAN           string // Required
+	BIC            string
+	Amount         amount // The currency is the CC key, CZK if it is missing
+	reference      string
+	name           string
+	due            string
+	message        string
+	variableSymbol string
+	specificSymbol string
+	constantSymbol string
+}
+
+type spaydField struct {
+	Field
+	key string // Key in the payload
+	get func(s *SPAYDCode) string
+	set func(s *SPAYDCode, value string) error
+}
+
+var spaydFields = []spaydField{
+	{
+		Field: Field{Line: 0, Name: "iban", Type: "string", MaxLen: 34, Required: true, Usage: "recipient IBAN"},
+		key:   "ACC",
+		get:   func(s *SPAYDCode) string { return s.IBAN },
+		set:   setSPAYDIBAN,
+	},
+	{
+		Field: Field{Line: 1, Name: "bic", Type: "string", MaxLen: 11, Usage: "`8` or `11` character, added to the account after a `+`"},
+		get:   func(s *SPAYDCode) string { return s.BIC },
+		set:   setSPAYDBIC,
+	},
+	{
+		Field: Field{Line: 2, Name: "amount", Type: "string", MaxLen: 10, Usage: "amount with at most two decimals, `0.01` - `9999999.99`"},
+		key:   "AM",
+		get:   getSPAYDAmount,
+		set:   setSPAYDAmount,
+	},
+	{
+		Field: Field{Line: 3, Name: "currency", Type: "string", MaxLen: 3, Usage: "ISO 4217 currency code, `CZK` if it is missing"},
+		key:   "CC",
+		get:   func(s *SPAYDCode) string { return s.Amount.currency },
+		set:   setSPAYDCurrency,
+	},
+	{
+		Field: Field{Line: 4, Name: "reference", Type: "string", MaxLen: 16, Usage: "payment reference for the recipient, digits only"},
+		key:   "RF",
+		get:   func(s *SPAYDCode) string { return s.reference },
+		set:   func(s *SPAYDCode, v string) error { return s.Reference(v) },
+	},
+	{
+		Field: Field{Line: 5, Name: "name", Type: "string", MaxLen: 35, Usage: "recipient name"},
+		key:   "RN",
+		get:   func(s *SPAYDCode) string { return s.name },
+		set:   func(s *SPAYDCode, v string) error { return s.Name(v) },
+	},
+	{
+		Field: Field{Line: 6, Name: "dueDate", Type: "string", MaxLen: 8, Usage: "due date in `20060102` format"},
+		key:   "DT",
+		get:   func(s *SPAYDCode) string { return s.due },
+		set:   setSPAYDDue,
+	},
+	{
+		Field: Field{Line: 7, Name: "message", Type: "string", MaxLen: 60, Usage: "message for the recipient"},
+		key:   "MSG",
+		get:   func(s *SPAYDCode) string { return s.message },
+		set:   func(s *SPAYDCode, v string) error { return s.Message(v) },
+	},
+	{
+		Field: Field{Line: 8, Name: "variableSymbol", Type: "string", MaxLen: 10, Usage: "Czech variable symbol, digits only"},
+		key:   "X-VS",
+		get:   func(s *SPAYDCode) string { return s.variableSymbol },
+		set:   symbol("variableSymbol", func(s *SPAYDCode) *string { return &s.variableSymbol }),
+	},
+	{
+		Field: Field{Line: 9, Name: "specificSymbol", Type: "string", MaxLen: 10, Usage: "Czech specific symbol, digits only"},
+		key:   "X-SS",
+		get:   func(s *SPAYDCode) string { return s.specificSymbol },
+		set:   symbol("specificSymbol", func(s *SPAYDCode) *string { return &s.specificSymbol }),
+	},
+	{
+		Field: Field{Line: 10, Name: "constantSymbol", Type: "string", MaxLen: 10, Usage: "Czech constant symbol, digits only"},
+		key:   "X-KS",
+		get:   func(s *SPAYDCode) string { return s.constantSymbol },
+		set:   symbol("constantSymbol", func(s *SPAYDCode) *string { return &s.constantSymbol }),
+	},
+}
+
+// SPAYDFields of the SPAYD code in payload order
+func SPAYDFields() []Field {
+	fields := make([]Field, 0, len(spaydFields))
+	for _, f := range spaydFields {
+		set := f.set
+		field := f.Field
+		field.check = func(v string) error { return set(&SPAYDCode{}, v) }
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// NewSPAYDPayment creates a SPAYD code for the account
+func NewSPAYDPayment(iban string) (*SPAYDCode, error) {
+	s := &SPAYDCode{}
+	if err := setSPAYDIBAN(s, iban); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ParseSPAYD reads a SPAYD payload, the unknown keys are ignored as the standard requires
+func ParseSPAYD(content string) (*SPAYDCode, error) {
+	if !strings.HasPrefix(content, spaydHeader) {
+		return nil, errors.New("invalid SPAYD header")
+	}
+
+	s := &SPAYDCode{}
+	for _, pair := range strings.Split(strings.TrimSuffix(content[len(spaydHeader):], "*"), "*") {
+		i := strings.Index(pair, ":")
+		if i < 1 {
+			return nil, fmt.Errorf("invalid SPAYD pair: %s", pair)
+		}
+
+		key, value := pair[:i], strings.ReplaceAll(pair[i+1:], "%2A", "*")
+		if key == "ACC" {
+			iban, bic := value, ""
+			if j := strings.Index(value, "+"); j >= 0 {
+				iban, bic = value[:j], value[j+1:]
+			}
+			if err := setSPAYDIBAN(s, iban); err != nil {
+				return nil, err
+			}
+			if err := setSPAYDBIC(s, bic); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		for _, f := range spaydFields {
+			if f.key != key {
+				continue
+			}
+			if err := f.set(s, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Set a field by its name
+func (s *SPAYDCode) Set(name string, value string) error {
+	for _, f := range spaydFields {
+		if f.Name == name {
+			return f.set(s, value)
+		}
+	}
+	return fmt.Errorf("unknown field: %s", name)
+}
+
+// Get a field value by its name, it returns the value without the escaping of the payload
+func (s SPAYDCode) Get(name string) string {
+	for _, f := range spaydFields {
+		if f.Name == name {
+			return f.get(&s)
+		}
+	}
+	return ""
+}
+
+// HundredthsAmount for the transaction in hundredths of the currency, CZK if the currency is empty
+func (s *SPAYDCode) HundredthsAmount(total int, currency string) error {
+	if total < 1 {
+		return errors.New("amount should be at least 0.01")
+	}
+
+	if total > spaydMaxAmount {
+		return errors.New("amount could not be higher than 9999999.99")
+	}
+
+	if err := setSPAYDCurrency(s, currency); err != nil {
+		return err
+	}
+	s.Amount.total = total
+	s.Amount.decimals = 2
+	return nil
+}
+
+// Reference sets the payment reference, at most 16 digits
+func (s *SPAYDCode) Reference(ref string) error {
+	if len(ref) > 16 {
+		return errors.New("reference is too long")
+	}
+
+	if !IsDigits(ref) {
+		return errors.New("reference should contain only digits")
+	}
+	s.reference = ref
+	return nil
+}
+
+// Name sets the recipient name
+func (s *SPAYDCode) Name(name string) error {
+	if utf8.RuneCountInString(name) > 35 {
+		return errors.New("name should not be longer than 35")
+	}
+	s.name = name
+	return nil
+}
+
+// DueDate of the payment
+func (s *SPAYDCode) DueDate(t time.Time) {
+	s.due = t.Format(spaydDate)
+}
+
+// Message for the recipient
+func (s *SPAYDCode) Message(msg string) error {
+	if utf8.RuneCountInString(msg) > 60 {
+		return errors.New("message is too long")
+	}
+	s.message = msg
+	return nil
+}
+
+// Validate the required fields and the size
+func (s SPAYDCode) Validate() error {
+	if s.IBAN == "" {
+		return errors.New("iban is required")
+	}
+
+	if content := s.String(); len(content) > SPAYDCapacityOptions.MaxSize {
+		return fmt.Errorf("spayd content is too large: %d bytes, maximum is %d", len(content), SPAYDCapacityOptions.MaxSize)
+	}
+	return nil
+}
+
+// String returns the payload, only the set keys are added
+func (s SPAYDCode) String() string {
+	var sb strings.Builder
+	sb.WriteString(spaydHeader[:len(spaydHeader)-1])
+	for _, f := range spaydFields {
+		if f.key == "" {
+			continue // Part of an other key
+		}
+
+		value := f.get(&s)
+		if f.key == "ACC" && s.BIC != "" {
+			value += "+" + s.BIC
+		}
+		if value == "" {
+			continue
+		}
+
+		sb.WriteString("*" + f.key + ":" + strings.ReplaceAll(value, "*", "%2A"))
+	}
+	return sb.String()
+}
+
+func setSPAYDIBAN(s *SPAYDCode, iban string) error {
+	if err := ValidateIBAN(iban); err != nil {
+		return err
+	}
+	s.IBAN = iban
+	return nil
+}
+
+func setSPAYDBIC(s *SPAYDCode, bic string) error {
+	if bic != "" && len(bic) != 8 && len(bic) != 11 {
+		return errors.New("invalid BIC length")
+	}
+	s.BIC = bic
+	return nil
+}
+
+func getSPAYDAmount(s *SPAYDCode) string {
+	if s.Amount.total > 0 {
+		return s.Amount.Decimal()
+	}
+	return ""
+}
+
+func setSPAYDAmount(s *SPAYDCode, v string) error {
+	total, err := ParseDecimal(v, 2)
+	if err != nil {
+		return errors.New("invalid amount")
+	}
+	return s.HundredthsAmount(total, s.Amount.currency)
+}
+
+func setSPAYDCurrency(s *SPAYDCode, v string) error {
+	if err := checkCurrency(v); err != nil {
+		return err
+	}
+	s.Amount.currency = v
+	return nil
+}
+
+func setSPAYDDue(s *SPAYDCode, v string) error {
+	if _, err := time.Parse(spaydDate, v); v != "" && err != nil {
+		return errors.New("invalid due date")
+	}
+	s.due = v
+	return nil
+}
+
+func symbol(name string, field func(s *SPAYDCode) *string) func(s *SPAYDCode, v string) error {
+	return func(s *SPAYDCode, v string) error {
+		if len(v) > 10 || !IsDigits(v) {
+			return fmt.Errorf("invalid %s", name)
+		}
+		*field(s) = v
+		return nil
+	}
+}
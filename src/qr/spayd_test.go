@@ -0,0 +1,97 @@
+package qr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const spaydExample = "SPD*1.0*ACC:CZ5855000000001265098001+RZBCCZPP*AM:480.50*CC:CZK*RF:7004139146*MSG:PLATBA ZA ZBOZI*X-VS:1234567890"
+
+func TestSPAYDFormat(t *testing.T) {
+	s, err := NewSPAYDPayment("CZ5855000000001265098001")
+	assert.NoError(t, err)
+	assert.NoError(t, s.Set("bic", "RZBCCZPP"))
+	assert.NoError(t, s.HundredthsAmount(48050, "CZK"))
+	assert.NoError(t, s.Reference("7004139146"))
+	assert.NoError(t, s.Message("PLATBA ZA ZBOZI"))
+	assert.NoError(t, s.Set("variableSymbol", "1234567890"))
+
+	assert.Equal(t, spaydExample, s.String())
+	assert.NoError(t, s.Validate())
+
+	s.DueDate(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, s.Name("Jan * Novak"))
+	assert.Equal(t, "SPD*1.0*ACC:CZ5855000000001265098001+RZBCCZPP*AM:480.50*CC:CZK*RF:7004139146*RN:Jan %2A Novak*DT:20200301*MSG:PLATBA ZA ZBOZI*X-VS:1234567890", s.String())
+	assert.Equal(t, "Jan * Novak", s.Get("name"))
+}
+
+func TestParseSPAYD(t *testing.T) {
+	s, err := ParseSPAYD(spaydExample)
+	assert.NoError(t, err)
+	assert.Equal(t, "CZ5855000000001265098001", s.IBAN)
+	assert.Equal(t, "RZBCCZPP", s.BIC)
+	assert.Equal(t, "CZK", s.Amount.Currency())
+	assert.Equal(t, "480.50", s.Amount.Decimal())
+	assert.Equal(t, "1234567890", s.Get("variableSymbol"))
+	assert.Equal(t, spaydExample, s.String())
+
+	// Unknown keys, escaping and a trailing separator
+	s, err = ParseSPAYD("SPD*1.0*ACC:CZ5855000000001265098001*X-URL:http://example.com*MSG:A%2AB*")
+	assert.NoError(t, err)
+	assert.Equal(t, "A*B", s.Get("message"))
+	assert.Equal(t, "SPD*1.0*ACC:CZ5855000000001265098001*MSG:A%2AB", s.String())
+}
+
+func TestParseSPAYDErrors(t *testing.T) {
+	for content, msg := range map[string]string{
+		"SPD*2.0*ACC:CZ5855000000001265098001":                  "invalid SPAYD header",
+		"SPD*1.0*MSG:hello":                                     "iban is required",
+		"SPD*1.0*ACC:CZ5855000000001265098001*AM":               "invalid SPAYD pair: AM",
+		"SPD*1.0*ACC:CZ5855000000001265098002":                  "invalid IBAN checksum",
+		"SPD*1.0*ACC:CZ5855000000001265098001+ABC":              "invalid BIC length",
+		"SPD*1.0*ACC:CZ5855000000001265098001*AM:1.234":         "invalid amount",
+		"SPD*1.0*ACC:CZ5855000000001265098001*CC:czk":           "invalid currency",
+		"SPD*1.0*ACC:CZ5855000000001265098001*DT:20201301":      "invalid due date",
+		"SPD*1.0*ACC:CZ5855000000001265098001*RF:ABC":           "reference should contain only digits",
+		"SPD*1.0*ACC:CZ5855000000001265098001*X-KS:12345678901": "invalid constantSymbol",
+	} {
+		_, err := ParseSPAYD(content)
+		assert.EqualError(t, err, msg, content)
+	}
+}
+
+func TestSPAYDAmount(t *testing.T) {
+	s := &SPAYDCode{}
+	assert.EqualError(t, s.HundredthsAmount(0, ""), "amount should be at least 0.01")
+	assert.EqualError(t, s.HundredthsAmount(1000000000, ""), "amount could not be higher than 9999999.99")
+	assert.EqualError(t, s.HundredthsAmount(1, "EURO"), "invalid currency")
+
+	assert.NoError(t, s.Set("currency", "EUR"))
+	assert.NoError(t, s.Set("amount", "12,5"))
+	assert.Equal(t, "12.50", s.Get("amount"))
+	assert.Equal(t, "EUR", s.Get("currency"))
+}
+
+func TestFormatSPAYD(t *testing.T) {
+	f, ok := LookupFormat("spayd")
+	assert.True(t, ok)
+	assert.Equal(t, SPAYDCapacityOptions, f.Capacity())
+	assert.Len(t, f.Fields(), len(spaydFields))
+
+	p, err := f.Decode(spaydExample)
+	assert.NoError(t, err)
+
+	content, err := f.Encode(p)
+	assert.NoError(t, err)
+	assert.Equal(t, spaydExample, content)
+
+	_, err = f.Encode(f.New())
+	assert.EqualError(t, err, "iban is required")
+	assert.EqualError(t, f.Validate(&EPCCode{}), "payload is not a SPAYD code")
+
+	d, _, err := Detect(spaydExample)
+	assert.NoError(t, err)
+	assert.Equal(t, "SPAYD", d.Name())
+}
@@ -0,0 +1,172 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Template is a partially filled code, the per payment values come from patterns with {variable} placeholders
+// The recipient and the fixed fields are validated once by NewTemplate, the instantiation only fills the patterns.
+type Template struct {
+	base      Code
+	patterns  []pattern
+	variables []string
+}
+
+// pattern of a field, the parts are literals and variables after each other
+type pattern struct {
+	field Field
+	parts []patternPart
+}
+
+type patternPart struct {
+	text     string // Literal text or the variable name
+	variable bool
+}
+
+// NewTemplate validates the recipient of the base code and parses the patterns of the optional fields
+// The patterns are field name and pattern pairs, e.g. "message": "Számla {invoice}", "amount": "{amount}".
+// A pattern without placeholders is set on the base code as a fixed value.
+func NewTemplate(base Code, patterns map[string]string) (*Template, error) {
+	if base.Kind != KindHCT && base.Kind != KindRTP {
+		return nil, errors.New("invalid kind")
+	}
+
+	// The recipient is set again on a copy to run the field validations
+	probe := base
+	for _, name := range []string{"bic", "name", "iban"} {
+		value := base.Get(name)
+		if value == "" {
+			return nil, fmt.Errorf("%s is required", name)
+		}
+		if err := probe.Set(name, value); err != nil {
+			return nil, err
+		}
+	}
+
+	for name := range patterns {
+		if _, ok := LookupField(name); !ok {
+			return nil, fmt.Errorf("unknown field: %s", name)
+		}
+	}
+
+	t := &Template{base: base}
+	variables := make(map[string]bool)
+	for _, f := range Fields {
+		value, ok := patterns[f.Name]
+		if !ok {
+			continue
+		}
+
+		if f.Required {
+			return nil, fmt.Errorf("%s could not be a pattern", f.Name)
+		}
+
+		parts, err := parsePattern(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s pattern: %v", f.Name, err)
+		}
+
+		fixed := 0
+		for _, p := range parts {
+			if p.variable {
+				variables[p.text] = true
+			} else {
+				fixed += len(p.text)
+			}
+		}
+
+		if fixed == len(value) {
+			if err := t.base.Set(f.Name, value); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if fixed > f.MaxLen {
+			return nil, fmt.Errorf("%s pattern is too long", f.Name)
+		}
+		t.patterns = append(t.patterns, pattern{field: f, parts: parts})
+	}
+
+	for v := range variables {
+		t.variables = append(t.variables, v)
+	}
+	sort.Strings(t.variables)
+	return t, nil
+}
+
+// Variables used by the patterns in alphabetical order
+func (t Template) Variables() []string {
+	return append([]string(nil), t.variables...)
+}
+
+// Code creates a code from the template, every variable of the patterns should be set
+func (t Template) Code(variables map[string]string, validUntil time.Time) (*Code, error) {
+	c := t.base
+	var sb strings.Builder
+	for _, p := range t.patterns {
+		sb.Reset()
+		for _, part := range p.parts {
+			if !part.variable {
+				sb.WriteString(part.text)
+				continue
+			}
+
+			value, ok := variables[part.text]
+			if !ok {
+				return nil, fmt.Errorf("missing variable: %s", part.text)
+			}
+			sb.WriteString(value)
+		}
+
+		if err := c.Set(p.field.Name, sb.String()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.ValidUntil(validUntil); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// parsePattern splits the pattern to literals and {variable} placeholders, the variable names are letters, digits or _
+func parsePattern(s string) ([]patternPart, error) {
+	var parts []patternPart
+	for s != "" {
+		start := strings.IndexAny(s, "{}")
+		if start < 0 {
+			parts = append(parts, patternPart{text: s})
+			break
+		}
+
+		if s[start] == '}' {
+			return nil, errors.New("unexpected }")
+		}
+
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return nil, errors.New("missing }")
+		}
+
+		name := s[start+1 : start+end]
+		if name == "" || strings.IndexFunc(name, func(r rune) bool { return !isVariableRune(r) }) >= 0 {
+			return nil, fmt.Errorf("invalid variable name: %q", name)
+		}
+
+		if start > 0 {
+			parts = append(parts, patternPart{text: s[:start]})
+		}
+		parts = append(parts, patternPart{text: name, variable: true})
+		s = s[start+end+1:]
+	}
+	return parts, nil
+}
+
+func isVariableRune(r rune) bool {
+	return r == '_' || (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
@@ -0,0 +1,108 @@
+package qr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func templateBase(t *testing.T) Code {
+	c, err := NewPaymentSend("OTPVHUHB", "Shop Ltd", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	assert.NoError(t, c.Purpose("GDSV"))
+	return *c
+}
+
+func TestTemplate(t *testing.T) {
+	tmpl, err := NewTemplate(templateBase(t), map[string]string{
+		"amount":    "{amount}",
+		"invoiceID": "{invoice}",
+		"message":   "Számla {invoice}",
+		"shopID":    "WEB",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"amount", "invoice"}, tmpl.Variables())
+
+	valid := time.Now().Add(time.Hour)
+	c, err := tmpl.Code(map[string]string{"invoice": "INV-1", "amount": "1500", "unused": "x"}, valid)
+	assert.NoError(t, err)
+	assert.Equal(t, "HUF1500", c.Get("amount"))
+	assert.Equal(t, "INV-1", c.Get("invoiceID"))
+	assert.Equal(t, "Számla INV-1", c.Get("message"))
+	assert.Equal(t, "WEB", c.Get("shopID"))
+	assert.Equal(t, "GDSV", c.Get("purpose"))
+	assert.NoError(t, c.Validate())
+
+	// The instances are independent
+	other, err := tmpl.Code(map[string]string{"invoice": "INV-2", "amount": "2000"}, valid)
+	assert.NoError(t, err)
+	assert.Equal(t, "Számla INV-2", other.Get("message"))
+	assert.Equal(t, "Számla INV-1", c.Get("message"))
+}
+
+func TestTemplateCodeErrors(t *testing.T) {
+	tmpl, err := NewTemplate(templateBase(t), map[string]string{"amount": "{amount}", "invoiceID": "INV-{invoice}"})
+	assert.NoError(t, err)
+
+	valid := time.Now().Add(time.Hour)
+	_, err = tmpl.Code(map[string]string{"amount": "1500"}, valid)
+	assert.EqualError(t, err, "missing variable: invoice")
+
+	_, err = tmpl.Code(map[string]string{"amount": "abc", "invoice": "1"}, valid)
+	assert.EqualError(t, err, "invalid amount")
+
+	_, err = tmpl.Code(map[string]string{"amount": "1", "invoice": "123456789012345678901234567890123"}, valid)
+	assert.EqualError(t, err, "invoiceID is too long")
+
+	_, err = tmpl.Code(map[string]string{"amount": "1", "invoice": "1"}, time.Now().Add(-time.Hour))
+	assert.EqualError(t, err, "negative validity period")
+}
+
+func TestNewTemplateErrors(t *testing.T) {
+	_, err := NewTemplate(Code{}, nil)
+	assert.EqualError(t, err, "invalid kind")
+
+	_, err = NewTemplate(Code{Kind: KindHCT, BIC: "OTPVHUHBXXX", Name: "Shop Ltd", IBAN: "x"}, nil)
+	assert.EqualError(t, err, "invalid IBAN length")
+
+	_, err = NewTemplate(Code{Kind: KindHCT, BIC: "OTP", Name: "Shop Ltd", IBAN: "HU42117730161111101800000000"}, nil)
+	assert.EqualError(t, err, "invalid BIC length")
+
+	_, err = NewTemplate(Code{Kind: KindHCT, BIC: "OTPVHUHBXXX"}, nil)
+	assert.EqualError(t, err, "name is required")
+
+	for patterns, msg := range map[string]string{
+		"unknown": "unknown field: unknown",
+		"iban":    "iban could not be a pattern",
+	} {
+		_, err = NewTemplate(templateBase(t), map[string]string{patterns: "{x}"})
+		assert.EqualError(t, err, msg)
+	}
+
+	for pattern, msg := range map[string]string{
+		"Számla {invoice":   "invalid message pattern: missing }",
+		"Számla invoice}":   "invalid message pattern: unexpected }",
+		"Számla {}":         "invalid message pattern: invalid variable name: \"\"",
+		"Számla {in voice}": "invalid message pattern: invalid variable name: \"in voice\"",
+	} {
+		_, err = NewTemplate(templateBase(t), map[string]string{"message": pattern})
+		assert.EqualError(t, err, msg, pattern)
+	}
+
+	_, err = NewTemplate(templateBase(t), map[string]string{"shopID": "{x}" + string(make([]byte, 36))})
+	assert.EqualError(t, err, "shopID pattern is too long")
+
+	_, err = NewTemplate(templateBase(t), map[string]string{"purpose": "XXXX"})
+	assert.EqualError(t, err, "invalid purpose code")
+}
+
+func TestParsePattern(t *testing.T) {
+	parts, err := parsePattern("{a}-{b_2}x")
+	assert.NoError(t, err)
+	assert.Equal(t, []patternPart{{text: "a", variable: true}, {text: "-"}, {text: "b_2", variable: true}, {text: "x"}}, parts)
+
+	parts, err = parsePattern("")
+	assert.NoError(t, err)
+	assert.Empty(t, parts)
+}
@@ -0,0 +1,22 @@
+package qr
+
+import "unicode/utf8"
+
+// Truncate cuts the string to at most n bytes without breaking a character
+func Truncate(s string, n int) string {
+	for len(s) > n {
+		_, size := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-size]
+	}
+	return s
+}
+
+// IsDigits reports whether the string contains only the digits 0-9, an empty string does
+func IsDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
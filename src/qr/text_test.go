@@ -0,0 +1,21 @@
+package qr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "abc", Truncate("abc", 5))
+	assert.Equal(t, "ab", Truncate("abc", 2))
+	assert.Equal(t, "ő", Truncate("őű", 3), "the character is not broken")
+	assert.Equal(t, "", Truncate("ő", 1))
+}
+
+func TestIsDigits(t *testing.T) {
+	assert.True(t, IsDigits("0123456789"))
+	assert.True(t, IsDigits(""))
+	assert.False(t, IsDigits("12a"))
+	assert.False(t, IsDigits("١٢"))
+}
@@ -0,0 +1,69 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"image"
+
+	"github.com/makiuchi-d/gozxing"
+	zxingqr "github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// minModulePixels is the smallest module size accepted by the verification
+// The encoder enlarges the smaller images to one pixel per module, those are readable by software only.
+const minModulePixels = 2
+
+// Character sets of the encoded payloads, the names are known by the decoder
+const (
+	charsetUTF8   = "UTF-8"
+	charsetLatin1 = "ISO-8859-1"
+)
+
+// readBack is the text a reader should get back from a rendered code and the character set of its bytes
+type readBack struct {
+	text    string
+	charset string
+}
+
+// VerifyImage decodes a rendered code from the image and compares it with the expected UTF-8 content
+// The decoder looks for the finder patterns like a camera would, so a distorted rendering fails too.
+func VerifyImage(img image.Image, content string) error {
+	return verifyImage(img, readBack{text: content, charset: charsetUTF8})
+}
+
+func verifyImage(img image.Image, expected readBack) error {
+	bitmap, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return fmt.Errorf("rendered code is unreadable: %v", err)
+	}
+
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_CHARACTER_SET: expected.charset}
+	result, err := zxingqr.NewQRCodeReader().Decode(bitmap, hints)
+	if err != nil {
+		return fmt.Errorf("rendered code is unreadable: %v", err)
+	}
+
+	if result.GetText() != expected.text {
+		return errors.New("rendered code does not match the payload")
+	}
+	return nil
+}
+
+// verify checks the module size of the image size and decodes the bitmap rendered in that size
+func verify(bitmap [][]bool, size int, expected readBack) error {
+	if err := checkModuleSize(len(bitmap), size); err != nil {
+		return err
+	}
+
+	img := bitmapImage(bitmap, size)
+	defer pngImages.Put(img)
+	return verifyImage(img, expected)
+}
+
+// checkModuleSize checks if the image size gives at least minModulePixels for a module
+func checkModuleSize(modules int, size int) error {
+	if size < modules*minModulePixels {
+		return fmt.Errorf("image size %d is too small, the code has %d modules, at least %d pixels are needed", size, modules, modules*minModulePixels)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package qr
+
+import (
+	"bytes"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyImage(t *testing.T) {
+	q, err := qrcode.New("HCT\n001\n", qrcode.Medium)
+	assert.NoError(t, err)
+
+	assert.NoError(t, VerifyImage(q.Image(128), "HCT\n001\n"))
+	assert.EqualError(t, VerifyImage(q.Image(128), "RTP\n001\n"), "rendered code does not match the payload")
+
+	blank := image.NewGray(image.Rect(0, 0, 128, 128))
+	for i := range blank.Pix {
+		blank.Pix[i] = 0xff
+	}
+	err = VerifyImage(blank, "HCT\n001\n")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "rendered code is unreadable")
+}
+
+func TestWritePNGVerify(t *testing.T) {
+	c, err := NewPaymentSend("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(t, err)
+	assert.NoError(t, c.ValidUntil(time.Now().Add(time.Hour)))
+
+	var buf bytes.Buffer
+	assert.NoError(t, c.WritePNG(&buf, RenderOptions{Size: 256, Verify: true}))
+	assert.NotZero(t, buf.Len())
+
+	buf.Reset()
+	assert.EqualError(t, c.WritePNG(&buf, RenderOptions{Size: 5, Verify: true}), "image size 5 is too small, the code has 45 modules, at least 90 pixels are needed")
+	assert.Zero(t, buf.Len())
+
+	// Without verification the encoder enlarges the image
+	assert.NoError(t, c.WritePNG(&buf, RenderOptions{Size: 5}))
+}
+
+func TestWriteSVGVerify(t *testing.T) {
+	c, err := NewPaymentSend("abcdefgh", "Test User", "HU00123456789012345678901234")
+	assert.NoError(t, err)
+	assert.NoError(t, c.ValidUntil(time.Now().Add(time.Hour)))
+
+	var buf bytes.Buffer
+	assert.NoError(t, c.WriteSVG(&buf, RenderOptions{Verify: true}))
+	assert.NotZero(t, buf.Len())
+
+	buf.Reset()
+	assert.Error(t, c.WriteSVG(&buf, RenderOptions{Size: 50, Verify: true}))
+	assert.Zero(t, buf.Len())
+}
+
+func TestFormatWritePNGVerify(t *testing.T) {
+	f, _ := LookupFormat("EPC")
+	e, err := NewEPCPayment("BFSWDE33BER", "Wikimedia Foerdergesellschaft", "DE33100205000001194700")
+	assert.NoError(t, err)
+
+	for _, size := range []int{100, 128, 200, 256, 300, 512} {
+		var buf bytes.Buffer
+		assert.NoError(t, WritePNG(&buf, f, e, RenderOptions{Size: size, Verify: true}), size)
+	}
+}
+
+func TestWritePNGVerifyLatin1(t *testing.T) {
+	e, err := NewEPCPayment("BFSWDE33BER", "Jörg Müller", "DE33100205000001194700")
+	assert.NoError(t, err)
+	e.Charset = 2
+
+	var buf bytes.Buffer
+	assert.NoError(t, e.WritePNG(&buf, RenderOptions{Size: 256, Verify: true}))
+	assert.NoError(t, e.WriteSVG(&buf, RenderOptions{Verify: true}))
+
+	f, _ := LookupFormat("EPC")
+	assert.NoError(t, WritePNG(&buf, f, e, RenderOptions{Size: 256, Verify: true}))
+}
@@ -0,0 +1,236 @@
+package reconcile
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// Transaction is a single booked transaction of a statement or a notification
+type Transaction struct {
+	Account     string // IBAN of the statement account
+	Amount      int    // In hundredths of the currency
+	Currency    string
+	Credit      bool
+	BookingDate time.Time
+	EndToEndID  string
+	Reference   string // Bank reference of the entry or the transaction
+	DebtorName  string
+	DebtorIBAN  string
+	Remittance  []string // Unstructured remittance lines and structured creditor references
+	Additional  string   // Additional entry or transaction information
+}
+
+// Text returns every free text field of the transaction for the identifier search
+func (t Transaction) Text() string {
+	return strings.Join(append([]string{t.EndToEndID, t.Additional}, t.Remittance...), "\n")
+}
+
+// CreditorReference returns the RF creditor reference of the transaction texts, empty without any
+func (t Transaction) CreditorReference() string {
+	return qr.FindCreditorReference(t.Text())
+}
+
+type camtDocument struct {
+	XMLName       xml.Name
+	Statements    []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+	Notifications []camtStatement `xml:"BkToCstmrDbtCdtNtfctn>Ntfctn"`
+}
+
+type camtStatement struct {
+	Acct struct {
+		ID struct {
+			IBAN string
+		} `xml:"Id"`
+	}
+	Ntry []camtEntry
+}
+
+type camtEntry struct {
+	Amt          camtAmount
+	CdtDbtInd    string
+	Sts          camtStatus
+	BookgDt      camtDate
+	AcctSvcrRef  string
+	AddtlNtryInf string
+	TxDtls       []camtTransaction `xml:"NtryDtls>TxDtls"`
+}
+
+type camtAmount struct {
+	Ccy   string `xml:",attr"`
+	Value string `xml:",chardata"`
+}
+
+// camtStatus is a plain code before camt.053.001.08 and a choice of a code or a proprietary value since then
+type camtStatus struct {
+	Value string `xml:",chardata"`
+	Cd    string
+}
+
+func (s camtStatus) code() string {
+	if s.Cd != "" {
+		return s.Cd
+	}
+	return strings.TrimSpace(s.Value)
+}
+
+type camtDate struct {
+	Dt   string
+	DtTm string
+}
+
+type camtTransaction struct {
+	Refs struct {
+		AcctSvcrRef string
+		EndToEndID  string `xml:"EndToEndId"`
+	}
+	Amt     *camtAmount
+	AmtDtls struct {
+		TxAmt struct {
+			Amt *camtAmount
+		}
+	}
+	CdtDbtInd string
+	RltdPties struct {
+		Dbtr struct {
+			Nm  string
+			Pty struct {
+				Nm string
+			}
+		}
+		DbtrAcct struct {
+			ID struct {
+				IBAN string
+			} `xml:"Id"`
+		}
+	}
+	RmtInf struct {
+		Ustrd []string
+		Strd  []struct {
+			CdtrRefInf struct {
+				Ref string
+			}
+		}
+	}
+	AddtlTxInf string
+}
+
+// ParseCAMT reads the booked transactions of a camt.053 statement or a camt.054 notification (any version), the
+// pending and informational entries are skipped
+func ParseCAMT(b []byte) ([]Transaction, error) {
+	var doc camtDocument
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.XMLName.Local != "Document" || (!strings.Contains(doc.XMLName.Space, "camt.053") && !strings.Contains(doc.XMLName.Space, "camt.054")) {
+		return nil, errors.New("not a camt.053 or camt.054 document")
+	}
+
+	var transactions []Transaction
+	for _, s := range append(doc.Statements, doc.Notifications...) {
+		for _, e := range s.Ntry {
+			if e.Sts.code() != "BOOK" {
+				continue
+			}
+
+			txs, err := entryTransactions(s.Acct.ID.IBAN, e)
+			if err != nil {
+				return nil, err
+			}
+			transactions = append(transactions, txs...)
+		}
+	}
+	return transactions, nil
+}
+
+// entryTransactions splits the entry to its transaction details, the entry amount is used if there's only one
+func entryTransactions(account string, e camtEntry) ([]Transaction, error) {
+	amount, err := qr.ParseDecimal(strings.TrimSpace(e.Amt.Value), 2)
+	if err != nil {
+		return nil, errors.New("invalid amount: " + e.Amt.Value)
+	}
+
+	booking, err := parseDate(e.BookgDt)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := Transaction{
+		Account:     account,
+		Amount:      amount,
+		Currency:    e.Amt.Ccy,
+		Credit:      e.CdtDbtInd == "CRDT",
+		BookingDate: booking,
+		Reference:   e.AcctSvcrRef,
+		Additional:  e.AddtlNtryInf,
+	}
+	if len(e.TxDtls) == 0 {
+		return []Transaction{entry}, nil
+	}
+
+	txs := make([]Transaction, 0, len(e.TxDtls))
+	for _, d := range e.TxDtls {
+		tx := entry
+		tx.EndToEndID = d.Refs.EndToEndID
+		if d.Refs.AcctSvcrRef != "" {
+			tx.Reference = d.Refs.AcctSvcrRef
+		}
+		if d.CdtDbtInd != "" {
+			tx.Credit = d.CdtDbtInd == "CRDT"
+		}
+
+		amt := d.Amt
+		if amt == nil {
+			amt = d.AmtDtls.TxAmt.Amt
+		}
+		if amt != nil {
+			if tx.Amount, err = qr.ParseDecimal(strings.TrimSpace(amt.Value), 2); err != nil {
+				return nil, errors.New("invalid amount: " + amt.Value)
+			}
+			tx.Currency = amt.Ccy
+		} else if len(e.TxDtls) > 1 {
+			return nil, errors.New("missing transaction amount in a batch entry")
+		}
+
+		tx.DebtorName = d.RltdPties.Dbtr.Nm
+		if tx.DebtorName == "" {
+			tx.DebtorName = d.RltdPties.Dbtr.Pty.Nm
+		}
+		tx.DebtorIBAN = d.RltdPties.DbtrAcct.ID.IBAN
+
+		tx.Remittance = append([]string{}, d.RmtInf.Ustrd...)
+		for _, s := range d.RmtInf.Strd {
+			if s.CdtrRefInf.Ref != "" {
+				tx.Remittance = append(tx.Remittance, s.CdtrRefInf.Ref)
+			}
+		}
+		if d.AddtlTxInf != "" {
+			tx.Additional = d.AddtlTxInf
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+func parseDate(d camtDate) (time.Time, error) {
+	switch {
+	case d.Dt != "":
+		t, err := time.Parse("2006-01-02", d.Dt)
+		if err != nil {
+			return time.Time{}, errors.New("invalid booking date: " + d.Dt)
+		}
+		return t, nil
+	case d.DtTm != "":
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+			if t, err := time.Parse(layout, d.DtTm); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, errors.New("invalid booking date: " + d.DtTm)
+	}
+	return time.Time{}, nil
+}
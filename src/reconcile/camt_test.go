@@ -0,0 +1,74 @@
+package reconcile
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readTransactions(t *testing.T, file string) []Transaction {
+	b, err := ioutil.ReadFile(file)
+	assert.NoError(t, err)
+
+	txs, err := ParseCAMT(b)
+	assert.NoError(t, err)
+	return txs
+}
+
+func TestParseCAMT053(t *testing.T) {
+	txs := readTransactions(t, "testdata/camt053.xml")
+	assert.Len(t, txs, 5)
+
+	assert.Equal(t, Transaction{
+		Account:     "HU42117730161111101800000000",
+		Amount:      150000,
+		Currency:    "HUF",
+		Credit:      true,
+		BookingDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		EndToEndID:  "NOTPROVIDED",
+		Reference:   "REF1",
+		DebtorName:  "Test Payer",
+		DebtorIBAN:  "HU93116000060000000012345676",
+		Remittance:  []string{"Payment for inv-2024 001"},
+	}, txs[0])
+
+	assert.Equal(t, 100000, txs[1].Amount)
+	assert.Equal(t, "REF2-1", txs[1].Reference)
+	assert.Equal(t, "Other Payer", txs[1].DebtorName)
+	assert.Equal(t, "INV-2024-002", txs[1].EndToEndID)
+	assert.Equal(t, 2024, txs[1].BookingDate.Year())
+
+	assert.Equal(t, 200000, txs[2].Amount)
+	assert.Equal(t, []string{"RF18539007547034"}, txs[2].Remittance)
+
+	assert.Equal(t, 70000, txs[3].Amount)
+	assert.Equal(t, "Transfer", txs[3].Additional)
+	assert.False(t, txs[4].Credit) // The pending entry is skipped
+}
+
+func TestParseCAMT054(t *testing.T) {
+	txs := readTransactions(t, "testdata/camt054.xml")
+	assert.Len(t, txs, 1)
+	assert.Equal(t, 25050, txs[0].Amount)
+	assert.Equal(t, "Instant transfer", txs[0].Additional)
+	assert.Equal(t, "\nInstant transfer\nOrder 77", txs[0].Text())
+}
+
+func TestParseCAMTErrors(t *testing.T) {
+	_, err := ParseCAMT([]byte(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"/>`))
+	assert.EqualError(t, err, "not a camt.053 or camt.054 document")
+
+	_, err = ParseCAMT([]byte(`<Document`))
+	assert.Error(t, err)
+
+	_, err = ParseCAMT([]byte(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"><BkToCstmrStmt><Stmt><Ntry><Sts>BOOK</Sts><Amt>1.234</Amt></Ntry></Stmt></BkToCstmrStmt></Document>`))
+	assert.EqualError(t, err, "invalid amount: 1.234")
+
+	_, err = ParseCAMT([]byte(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"><BkToCstmrStmt><Stmt><Ntry><Sts>BOOK</Sts><Amt>1</Amt><BookgDt><Dt>2024.01.01</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`))
+	assert.EqualError(t, err, "invalid booking date: 2024.01.01")
+
+	_, err = ParseCAMT([]byte(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"><BkToCstmrStmt><Stmt><Ntry><Sts>BOOK</Sts><Amt>2</Amt><NtryDtls><TxDtls/><TxDtls/></NtryDtls></Ntry></Stmt></BkToCstmrStmt></Document>`))
+	assert.EqualError(t, err, "missing transaction amount in a batch entry")
+}
@@ -0,0 +1,161 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// CSVMapping describes a bank specific CSV export, the columns are referred by their header names
+// Either Amount (signed, negative for debit) or Credit and Debit (separate positive columns) should be set.
+type CSVMapping struct {
+	Comma        string   `json:"comma"`        // Field separator, ";" by default
+	Skip         int      `json:"skip"`         // Lines before the header line
+	DateLayout   string   `json:"dateLayout"`   // Go time layout, "2006.01.02" by default
+	DecimalComma bool     `json:"decimalComma"` // "1 500,00" instead of "1,500.00"
+	Account      string   `json:"account"`      // Own account, the Hungarian account numbers are converted to IBAN
+	Date         string   `json:"date"`
+	Amount       string   `json:"amount"`
+	Credit       string   `json:"credit"`
+	Debit        string   `json:"debit"`
+	Currency     string   `json:"currency"`
+	DebtorName   string   `json:"debtorName"`
+	DebtorIBAN   string   `json:"debtorIban"`
+	Reference    string   `json:"reference"`
+	Remittance   []string `json:"remittance"` // Every column is added to the remittance lines
+}
+
+// ParseCSV reads a bank CSV export with the given column mapping, the file should be UTF-8
+func ParseCSV(r io.Reader, m CSVMapping) ([]Transaction, error) {
+	if m.Amount == "" && m.Credit == "" {
+		return nil, errors.New("amount or credit column is required")
+	}
+
+	comma := m.Comma
+	if comma == "" {
+		comma = ";"
+	}
+	c, _ := utf8.DecodeRuneInString(comma)
+	if c == utf8.RuneError || utf8.RuneCountInString(comma) != 1 {
+		return nil, errors.New("invalid comma")
+	}
+
+	layout := m.DateLayout
+	if layout == "" {
+		layout = "2006.01.02"
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = c
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) <= m.Skip {
+		return nil, errors.New("missing header line")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[m.Skip] {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+
+	for _, name := range append([]string{m.Account, m.Date, m.Amount, m.Credit, m.Debit, m.Currency, m.DebtorName, m.DebtorIBAN, m.Reference}, m.Remittance...) {
+		if _, ok := columns[name]; name != "" && !ok {
+			return nil, fmt.Errorf("missing column: %s", name)
+		}
+	}
+
+	var transactions []Transaction
+	for i, record := range records[m.Skip+1:] {
+		value := func(name string) string {
+			if col, ok := columns[name]; ok && name != "" && col < len(record) {
+				return strings.TrimSpace(record[col])
+			}
+			return ""
+		}
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue // Empty line
+		}
+
+		tx, err := csvTransaction(m, layout, value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", m.Skip+i+2, err)
+		}
+		transactions = append(transactions, tx)
+	}
+	return transactions, nil
+}
+
+func csvTransaction(m CSVMapping, layout string, value func(name string) string) (Transaction, error) {
+	tx := Transaction{
+		Currency:   value(m.Currency),
+		DebtorName: value(m.DebtorName),
+		Reference:  value(m.Reference),
+	}
+
+	if account := value(m.Account); account != "" {
+		tx.Account = mt940Account(account)
+	}
+	if debtor := value(m.DebtorIBAN); debtor != "" {
+		tx.DebtorIBAN = mt940Account(debtor)
+	}
+
+	if date := value(m.Date); date != "" {
+		t, err := time.Parse(layout, date)
+		if err != nil {
+			return tx, fmt.Errorf("invalid date: %s", date)
+		}
+		tx.BookingDate = t
+	}
+
+	var err error
+	if m.Amount != "" {
+		tx.Amount, tx.Credit, err = parseSignedAmount(value(m.Amount), m.DecimalComma)
+	} else if credit := value(m.Credit); credit != "" {
+		tx.Amount, _, err = parseSignedAmount(credit, m.DecimalComma)
+		tx.Credit = true
+	} else {
+		tx.Amount, _, err = parseSignedAmount(value(m.Debit), m.DecimalComma)
+	}
+	if err != nil {
+		return tx, err
+	}
+
+	for _, name := range m.Remittance {
+		if v := value(name); v != "" {
+			tx.Remittance = append(tx.Remittance, v)
+		}
+	}
+	return tx, nil
+}
+
+// parseSignedAmount parses an amount with thousand separators, returns the absolute value and true if it's positive
+func parseSignedAmount(s string, decimalComma bool) (int, bool, error) {
+	positive := !strings.HasPrefix(s, "-")
+	s = strings.TrimLeft(s, "+-")
+
+	thousands, decimal := ",", "."
+	if decimalComma {
+		thousands, decimal = ".", ","
+	}
+	s = strings.NewReplacer(" ", "", " ", "", thousands, "").Replace(s)
+	s = strings.TrimSuffix(strings.Replace(s, decimal, ".", 1), ".")
+
+	amount, err := qr.ParseDecimal(s, 2)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid amount: %s", s)
+	}
+	return amount, positive, nil
+}
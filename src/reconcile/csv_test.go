@@ -0,0 +1,90 @@
+package reconcile
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const csvStatement = "Számlakivonat\n" +
+	"Számla;Dátum;Összeg;Pénznem;Partner;Partner számla;Közlemény;Azonosító\n" +
+	"11773016-11111018-00000000;2024.01.02;1 500,00;HUF;Test Payer;11600006-00000000-12345676;inv-2024 001;REF1\n" +
+	"11773016-11111018-00000000;2024.01.03;-250,50;HUF;Bank;;Bank fee;\n" +
+	"\n"
+
+var csvTestMapping = CSVMapping{
+	Skip:         1,
+	DecimalComma: true,
+	Account:      "Számla",
+	Date:         "Dátum",
+	Amount:       "Összeg",
+	Currency:     "Pénznem",
+	DebtorName:   "Partner",
+	DebtorIBAN:   "Partner számla",
+	Reference:    "Azonosító",
+	Remittance:   []string{"Közlemény"},
+}
+
+func TestParseCSV(t *testing.T) {
+	txs, err := ParseCSV(strings.NewReader(csvStatement), csvTestMapping)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+
+	assert.Equal(t, Transaction{
+		Account:     "HU42117730161111101800000000",
+		Amount:      150000,
+		Currency:    "HUF",
+		Credit:      true,
+		BookingDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Reference:   "REF1",
+		DebtorName:  "Test Payer",
+		DebtorIBAN:  "HU93116000060000000012345676",
+		Remittance:  []string{"inv-2024 001"},
+	}, txs[0])
+
+	assert.False(t, txs[1].Credit)
+	assert.Equal(t, 25050, txs[1].Amount)
+	assert.Empty(t, txs[1].DebtorIBAN)
+}
+
+func TestParseCSVCreditDebit(t *testing.T) {
+	content := "\ufeffDate,Credit,Debit,Details\n2024-01-02,\"1,500.00\",,INV-1\n2024-01-03,,250.5,Fee\n"
+	txs, err := ParseCSV(strings.NewReader(content), CSVMapping{
+		Comma:      ",",
+		DateLayout: "2006-01-02",
+		Date:       "Date",
+		Credit:     "Credit",
+		Debit:      "Debit",
+		Remittance: []string{"Details"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, txs, 2)
+
+	assert.True(t, txs[0].Credit)
+	assert.Equal(t, 150000, txs[0].Amount)
+	assert.False(t, txs[1].Credit)
+	assert.Equal(t, 25050, txs[1].Amount)
+	assert.Equal(t, []string{"Fee"}, txs[1].Remittance)
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	_, err := ParseCSV(strings.NewReader(csvStatement), CSVMapping{})
+	assert.EqualError(t, err, "amount or credit column is required")
+
+	_, err = ParseCSV(strings.NewReader(csvStatement), CSVMapping{Comma: ";;", Amount: "Összeg"})
+	assert.EqualError(t, err, "invalid comma")
+
+	_, err = ParseCSV(strings.NewReader(csvStatement), CSVMapping{Skip: 5, Amount: "Összeg"})
+	assert.EqualError(t, err, "missing header line")
+
+	_, err = ParseCSV(strings.NewReader(csvStatement), CSVMapping{Skip: 1, Amount: "Amount"})
+	assert.EqualError(t, err, "missing column: Amount")
+
+	_, err = ParseCSV(strings.NewReader(csvStatement), CSVMapping{Skip: 1, Amount: "Összeg", Date: "Dátum", DateLayout: "2006-01-02"})
+	assert.EqualError(t, err, "line 3: invalid date: 2024.01.02")
+
+	_, err = ParseCSV(strings.NewReader(csvStatement), CSVMapping{Skip: 1, Amount: "Pénznem"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,174 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// ParseMT940 reads the statement lines (:61:) with their information (:86:) of one or more MT940 statements
+// The :86: field could be free text or structured with ?NN subfields (?20-?29 remittance, ?31 account, ?32-?33 name).
+func ParseMT940(b []byte) ([]Transaction, error) {
+	var transactions []Transaction
+	var account, currency string
+	var last *Transaction
+
+	for _, f := range mt940Fields(string(b)) {
+		switch f.tag {
+		case "20":
+			account, currency, last = "", "", nil
+		case "25":
+			account = mt940Account(f.value)
+		case "60F", "60M":
+			if len(f.value) >= 10 {
+				currency = f.value[7:10]
+			}
+		case "61":
+			tx, err := parseStatementLine(f.value)
+			if err != nil {
+				return nil, err
+			}
+			tx.Account = account
+			tx.Currency = currency
+			transactions = append(transactions, tx)
+			last = &transactions[len(transactions)-1]
+		case "86":
+			if last == nil {
+				continue
+			}
+			applyInformation(last, f.value)
+		}
+	}
+	return transactions, nil
+}
+
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+// mt940Fields splits the message to :tag: fields, the continuation lines are kept with new lines
+func mt940Fields(s string) []mt940Field {
+	var fields []mt940Field
+	for _, line := range strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, ":") {
+			if end := strings.Index(line[1:], ":"); end > 0 {
+				fields = append(fields, mt940Field{tag: line[1 : end+1], value: line[end+2:]})
+				continue
+			}
+		}
+
+		if len(fields) > 0 && line != "-" && !strings.HasPrefix(line, "{") {
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+	return fields
+}
+
+// mt940Account returns the account as IBAN, Hungarian account numbers are converted
+func mt940Account(s string) string {
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		s = s[i+1:]
+	}
+	s = strings.TrimSpace(s)
+
+	if iban, err := qr.HungarianIBAN(s); err == nil {
+		return iban
+	}
+	return strings.ReplaceAll(s, " ", "")
+}
+
+// parseStatementLine reads a :61: field: date, optional entry date, mark, optional funds code, amount, type,
+// customer reference, optional bank reference and supplementary details
+func parseStatementLine(s string) (Transaction, error) {
+	var tx Transaction
+	first := s
+	if i := strings.Index(s, "\n"); i >= 0 {
+		first, tx.Additional = s[:i], strings.TrimSpace(s[i+1:])
+	}
+
+	if len(first) < 6 {
+		return tx, fmt.Errorf("invalid statement line: %s", first)
+	}
+
+	date, err := time.Parse("060102", first[:6])
+	if err != nil {
+		return tx, fmt.Errorf("invalid statement line date: %s", first[:6])
+	}
+	tx.BookingDate = date
+	rest := first[6:]
+	if len(rest) >= 4 && qr.IsDigits(rest[:4]) {
+		rest = rest[4:] // Entry date
+	}
+
+	switch {
+	case strings.HasPrefix(rest, "RC"):
+		rest = rest[2:] // Reversal of a credit
+	case strings.HasPrefix(rest, "RD"):
+		tx.Credit = true
+		rest = rest[2:]
+	case strings.HasPrefix(rest, "C"):
+		tx.Credit = true
+		rest = rest[1:]
+	case strings.HasPrefix(rest, "D"):
+		rest = rest[1:]
+	default:
+		return tx, fmt.Errorf("invalid statement line mark: %s", first)
+	}
+
+	if len(rest) > 0 && (rest[0] < '0' || rest[0] > '9') {
+		rest = rest[1:] // Funds code
+	}
+
+	end := strings.IndexAny(rest, "NFS")
+	if end < 0 {
+		return tx, fmt.Errorf("invalid statement line: %s", first)
+	}
+	tx.Amount, err = qr.ParseDecimal(strings.TrimSuffix(rest[:end], ","), 2)
+	if err != nil {
+		return tx, fmt.Errorf("invalid amount: %s", rest[:end])
+	}
+
+	rest = rest[end:]
+	if len(rest) < 4 {
+		return tx, fmt.Errorf("invalid statement line type: %s", first)
+	}
+	rest = rest[4:]
+
+	if i := strings.Index(rest, "//"); i >= 0 {
+		rest, tx.Reference = rest[:i], rest[i+2:]
+	}
+	if rest != "NONREF" {
+		tx.EndToEndID = rest
+	}
+	return tx, nil
+}
+
+// applyInformation adds the :86: field to the transaction
+func applyInformation(tx *Transaction, s string) {
+	s = strings.ReplaceAll(s, "\n", "")
+	if !strings.HasPrefix(s, "?") && !(len(s) > 4 && s[3] == '?') {
+		tx.Remittance = append(tx.Remittance, s)
+		return
+	}
+
+	var name string
+	for _, sub := range strings.Split(s, "?")[1:] {
+		if len(sub) < 2 {
+			continue
+		}
+
+		code, value := sub[:2], sub[2:]
+		switch {
+		case code >= "20" && code <= "29", code >= "60" && code <= "63":
+			tx.Remittance = append(tx.Remittance, value)
+		case code == "31":
+			tx.DebtorIBAN = mt940Account(value)
+		case code == "32" || code == "33":
+			name += value
+		}
+	}
+	tx.DebtorName = name
+}
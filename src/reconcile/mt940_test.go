@@ -0,0 +1,53 @@
+package reconcile
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMT940(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/mt940.sta")
+	assert.NoError(t, err)
+
+	txs, err := ParseMT940(b)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 3)
+
+	assert.Equal(t, Transaction{
+		Account:     "HU42117730161111101800000000",
+		Amount:      150000,
+		Currency:    "HUF",
+		Credit:      true,
+		BookingDate: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Reference:   "REF1",
+		DebtorName:  "Test Payer",
+		DebtorIBAN:  "HU93116000060000000012345676",
+		Remittance:  []string{"Payment for inv-2024", " 001"},
+	}, txs[0])
+
+	assert.False(t, txs[1].Credit)
+	assert.Equal(t, 25050, txs[1].Amount)
+	assert.Equal(t, []string{"Bank fee"}, txs[1].Remittance)
+
+	assert.True(t, txs[2].Credit)
+	assert.Equal(t, 100000, txs[2].Amount)
+	assert.Equal(t, "INV-2024-002", txs[2].EndToEndID)
+	assert.Equal(t, "REF2", txs[2].Reference)
+	assert.Equal(t, "Transfer", txs[2].Additional)
+}
+
+func TestParseMT940Errors(t *testing.T) {
+	for _, s := range []string{
+		":61:24010",
+		":61:2401XXC100,00NTRF",
+		":61:240102X100,00NTRF",
+		":61:240102C100,00",
+		":61:240102C100,001NTRF",
+	} {
+		_, err := ParseMT940([]byte(s))
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,239 @@
+package reconcile
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// Status of an issued code after the reconciliation
+type Status string
+
+// Reconciliation statuses
+const (
+	StatusMatched   Status = "matched"   // Paid in full (or the code has no amount)
+	StatusPartial   Status = "partial"   // Paid less than the amount of the code
+	StatusUnmatched Status = "unmatched" // No payment found
+)
+
+// Match is the result of a single issued code
+type Match struct {
+	Code         qr.Code
+	Status       Status
+	Paid         int // Sum of the matched transactions in hundredths
+	Transactions []Transaction
+}
+
+// Report of the reconciliation
+type Report struct {
+	Matches   []Match       // In the order of the codes
+	Unmatched []Transaction // Credited transactions without a code
+}
+
+// Count returns the number of codes with the status
+func (r Report) Count(status Status) int {
+	count := 0
+	for _, m := range r.Matches {
+		if m.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Reconcile pairs the credited HUF transactions with the issued codes
+// A transaction belongs to a code if the account is the same (the payee IBAN of an HCT code, the payer IBAN of an RTP
+// code) and an identifier of the code is in the transaction texts as whole words. The identifiers are tried from the
+// strongest: the credTranID or the RF creditor reference, the invoiceID, then the message (it should equal a whole text
+// field). A transaction belongs to the code with the strongest match. Codes without these identifiers are matched by
+// the exact amount, except the transactions with an RF creditor reference, those belong to an identified code. A code
+// could be paid with more transactions, each transaction is used once.
+func Reconcile(codes []qr.Code, transactions []Transaction) Report {
+	used := make([]bool, len(transactions))
+	report := Report{Matches: make([]Match, len(codes))}
+	for i, c := range codes {
+		report.Matches[i] = Match{Code: c, Status: StatusUnmatched}
+	}
+
+	// Identified payments first, so an amount only match could not take them
+	for j, tx := range transactions {
+		best, bestStrength := -1, matchNone
+		for i, c := range codes {
+			strength := matchStrength(c, tx)
+			if strength == matchNone || strength < bestStrength {
+				continue
+			}
+
+			// On a tie the code which is paid in full by the transaction wins, then the first code
+			if strength > bestStrength || (!report.Matches[best].settledBy(tx) && report.Matches[i].settledBy(tx)) {
+				best, bestStrength = i, strength
+			}
+		}
+
+		if best >= 0 {
+			used[j] = true
+			report.Matches[best].add(tx)
+		}
+	}
+
+	for i, c := range codes {
+		amount := hundredths(c)
+		if identified(c) || amount == 0 {
+			continue
+		}
+
+		for j, tx := range transactions {
+			if used[j] || !applies(c, tx) || tx.Amount != amount || tx.CreditorReference() != "" {
+				continue
+			}
+			used[j] = true
+			report.Matches[i].add(tx)
+			break
+		}
+	}
+
+	for i := range report.Matches {
+		report.Matches[i].updateStatus()
+	}
+
+	for j, tx := range transactions {
+		if !used[j] && tx.Credit {
+			report.Unmatched = append(report.Unmatched, tx)
+		}
+	}
+	return report
+}
+
+func (m *Match) add(tx Transaction) {
+	m.Transactions = append(m.Transactions, tx)
+	m.Paid += tx.Amount
+}
+
+func (m *Match) updateStatus() {
+	switch {
+	case len(m.Transactions) == 0:
+		m.Status = StatusUnmatched
+	case m.Paid < hundredths(m.Code):
+		m.Status = StatusPartial
+	default:
+		m.Status = StatusMatched
+	}
+}
+
+// Identifier match strengths, from the weakest
+const (
+	matchNone = iota
+	matchMessage
+	matchInvoiceID
+	matchReference // credTranID or RF creditor reference
+)
+
+// identified returns true if the code has any identifier for the matching
+func identified(c qr.Code) bool {
+	for _, name := range []string{"credTranID", "invoiceID", "message"} {
+		if normalize(c.Get(name)) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// matchStrength returns the strongest identifier of the code found in the transaction
+func matchStrength(c qr.Code, tx Transaction) int {
+	if !applies(c, tx) {
+		return matchNone
+	}
+
+	words := tokens(tx.Text())
+	if containsWords(words, c.Get("credTranID")) {
+		return matchReference
+	}
+	if ref := c.FindCreditorReference(); ref != "" && ref == tx.CreditorReference() {
+		return matchReference
+	}
+	if containsWords(words, c.Get("invoiceID")) {
+		return matchInvoiceID
+	}
+
+	if message := normalize(c.Get("message")); message != "" {
+		for _, field := range append([]string{tx.EndToEndID, tx.Additional}, tx.Remittance...) {
+			if normalize(field) == message {
+				return matchMessage
+			}
+		}
+	}
+	return matchNone
+}
+
+// settledBy returns true if the transaction pays the rest of the code amount exactly
+func (m Match) settledBy(tx Transaction) bool {
+	return hundredths(m.Code)-m.Paid == tx.Amount
+}
+
+// applies checks the payee account of HCT and the payer account of RTP codes, credited HUF transactions only
+func applies(c qr.Code, tx Transaction) bool {
+	if !tx.Credit || (tx.Currency != "" && tx.Currency != "HUF") {
+		return false
+	}
+
+	account := tx.Account
+	if c.Kind == qr.KindRTP {
+		account = tx.DebtorIBAN
+	}
+	return account == "" || normalize(account) == normalize(c.IBAN)
+}
+
+// tokens splits the text to words of letters and digits in upper case
+func tokens(text string) []string {
+	return strings.FieldsFunc(strings.ToUpper(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// containsWords checks if the identifier is in the text as consecutive whole words, the separators are ignored as the
+// payers and the banks often reformat the texts (INV-2024-001 is found in "inv 2024/001" but not in "INV-2024-0012")
+func containsWords(words []string, id string) bool {
+	id = normalize(id)
+	if id == "" {
+		return false
+	}
+
+	for i := range words {
+		joined := ""
+		for _, w := range words[i:] {
+			joined += w
+			if len(joined) >= len(id) {
+				break
+			}
+		}
+		if joined == id {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize keeps only the letters and digits in upper case, the payers and the banks often reformat the texts
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, s)
+}
+
+// hundredths returns the HUF amount of the code in hundredths
+func hundredths(c qr.Code) int {
+	if c.Get("amount") == "" {
+		return 0
+	}
+
+	total, err := strconv.Atoi(c.Amount.Decimal())
+	if err != nil {
+		return 0
+	}
+	return total * 100
+}
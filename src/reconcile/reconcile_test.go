@@ -0,0 +1,129 @@
+package reconcile
+
+import (
+	"testing"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+	"github.com/stretchr/testify/assert"
+)
+
+func genCode(t *testing.T, amount int, set map[string]string) qr.Code {
+	c, err := qr.NewPaymentSend("OTPVHUHB", "Shop Ltd", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	if amount > 0 {
+		assert.NoError(t, c.HUFAmount(amount))
+	}
+	for name, value := range set {
+		assert.NoError(t, c.Set(name, value))
+	}
+	return *c
+}
+
+func TestReconcile(t *testing.T) {
+	txs := readTransactions(t, "testdata/camt053.xml")
+	codes := []qr.Code{
+		genCode(t, 1500, map[string]string{"invoiceID": "INV-2024-001"}), // Reformatted by the payer
+		genCode(t, 2000, map[string]string{"invoiceID": "INV-2024-002"}), // Only 1000 paid
+		genCode(t, 700, nil), // Amount only
+		genCode(t, 999, map[string]string{"invoiceID": "INV-2024-003"}), // Debit entry is not a payment
+		genCode(t, 0, map[string]string{"credTranID": "RF18539007547034"}),
+	}
+
+	report := Reconcile(codes, txs)
+	assert.Len(t, report.Matches, 5)
+
+	assert.Equal(t, StatusMatched, report.Matches[0].Status)
+	assert.Equal(t, 150000, report.Matches[0].Paid)
+	assert.Equal(t, "REF1", report.Matches[0].Transactions[0].Reference)
+
+	assert.Equal(t, StatusPartial, report.Matches[1].Status)
+	assert.Equal(t, 100000, report.Matches[1].Paid)
+
+	assert.Equal(t, StatusMatched, report.Matches[2].Status)
+	assert.Equal(t, 70000, report.Matches[2].Paid)
+
+	assert.Equal(t, StatusUnmatched, report.Matches[3].Status)
+	assert.Empty(t, report.Matches[3].Transactions)
+
+	assert.Equal(t, StatusMatched, report.Matches[4].Status)
+	assert.Equal(t, 200000, report.Matches[4].Paid)
+
+	assert.Empty(t, report.Unmatched)
+	assert.Equal(t, 3, report.Count(StatusMatched))
+	assert.Equal(t, 1, report.Count(StatusPartial))
+	assert.Equal(t, 1, report.Count(StatusUnmatched))
+}
+
+func TestReconcileUnmatched(t *testing.T) {
+	txs := readTransactions(t, "testdata/camt053.xml")
+
+	// Other account
+	c, err := qr.NewPaymentSend("OTPVHUHB", "Shop Ltd", "HU93116000060000000012345676")
+	assert.NoError(t, err)
+	assert.NoError(t, c.InvoiceID("INV-2024-002"))
+
+	report := Reconcile([]qr.Code{*c}, txs)
+	assert.Equal(t, StatusUnmatched, report.Matches[0].Status)
+	assert.Len(t, report.Unmatched, 4)
+}
+
+func TestReconcileRTP(t *testing.T) {
+	txs := readTransactions(t, "testdata/camt053.xml")
+
+	// The RTP code holds the payer account
+	c, err := qr.NewPaymentRequest("CIBHHUHB", "Test Payer", "HU93116000060000000012345676")
+	assert.NoError(t, err)
+	assert.NoError(t, c.HUFAmount(1500))
+
+	report := Reconcile([]qr.Code{*c}, txs)
+	assert.Equal(t, StatusMatched, report.Matches[0].Status)
+	assert.Equal(t, "Test Payer", report.Matches[0].Transactions[0].DebtorName)
+}
+
+func TestReconcileCreditorReference(t *testing.T) {
+	txs := readTransactions(t, "testdata/camt053.xml")
+	assert.Equal(t, "RF18539007547034", txs[2].CreditorReference())
+	assert.Equal(t, "", txs[0].CreditorReference())
+
+	// The transaction with a creditor reference is not taken by an amount only code
+	report := Reconcile([]qr.Code{genCode(t, 2000, nil)}, txs)
+	assert.Equal(t, StatusUnmatched, report.Matches[0].Status)
+
+	c := genCode(t, 2000, nil)
+	assert.NoError(t, c.CreditorReference("RF18 5390 0754 7034"))
+	report = Reconcile([]qr.Code{c}, txs)
+	assert.Equal(t, StatusMatched, report.Matches[0].Status)
+}
+
+func TestReconcileWholeWords(t *testing.T) {
+	txs := []Transaction{
+		{Amount: 50000000, Currency: "HUF", Credit: true, Remittance: []string{"Számla INV-10"}},
+		{Amount: 10000000, Currency: "HUF", Credit: true, Remittance: []string{"Számla INV-1"}},
+		{Amount: 20000000, Currency: "HUF", Credit: true, Remittance: []string{"Számla"}},
+	}
+	codes := []qr.Code{
+		genCode(t, 100000, map[string]string{"invoiceID": "INV-1", "message": "Számla"}),
+		genCode(t, 500000, map[string]string{"invoiceID": "INV-10", "message": "Számla"}),
+		genCode(t, 200000, map[string]string{"message": "Számla"}),
+	}
+
+	report := Reconcile(codes, txs)
+	assert.Equal(t, 10000000, report.Matches[0].Paid)
+	assert.Equal(t, StatusMatched, report.Matches[0].Status)
+	assert.Equal(t, 50000000, report.Matches[1].Paid)
+	assert.Equal(t, StatusMatched, report.Matches[1].Status)
+
+	// The message matches only a whole text field, the tie is won by the code which is settled by the amount
+	assert.Equal(t, 20000000, report.Matches[2].Paid)
+	assert.Equal(t, StatusMatched, report.Matches[2].Status)
+	assert.Empty(t, report.Unmatched)
+}
+
+func TestContainsWords(t *testing.T) {
+	words := tokens("Payment for inv-2024 001, RF18 5390 0754 7034")
+	assert.True(t, containsWords(words, "INV-2024-001"))
+	assert.True(t, containsWords(words, "RF18539007547034"))
+	assert.False(t, containsWords(words, "INV-2024-00"))
+	assert.False(t, containsWords(words, "NV-2024-001"))
+	assert.False(t, containsWords(words, "-"))
+}
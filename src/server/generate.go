@@ -3,22 +3,27 @@ package server
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gerifield/mnb-qr-go/src/qr"
 )
 
 var (
-	errInvalidKind = errors.New("invalid kind (should be RTP or HCT)")
-	errInvalidSize = errors.New("invalid PNG size")
+	errInvalidKind   = fmt.Errorf("invalid kind (should be one of %s)", strings.Join(qr.FormatNames(), ", "))
+	errInvalidSize   = errors.New("invalid PNG size")
+	errInvalidFormat = errors.New("invalid format (should be png or svg)")
 )
 
 type Srv struct {
+	templates *templateStore
 }
 
 func New() *Srv {
-	return &Srv{}
+	return &Srv{templates: newTemplateStore()}
 }
 
 func (s *Srv) GenerateHandler(w http.ResponseWriter, r *http.Request) {
@@ -27,134 +32,160 @@ func (s *Srv) GenerateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var input struct {
-		Kind    string `json:"kind"` // HCT/RTP
-		BIC     string `json:"bic"`
-		Name    string `json:"name"`
-		IBAN    string `json:"iban"`
-		Expire  int    `json:"expire"`  // Expire (duration) in seconds
-		PNGSize int    `json:"pngSize"` // Size in pixel
-
-		Amount     int    `json:"amount"`     // Optional, HUF only
-		Purpose    string `json:"purpose"`    // Optional
-		Message    string `json:"message"`    // Optional
-		ShopID     string `json:"shopID"`     // Optional
-		MerchDevID string `json:"merchDevID"` // Optional
-		InvoiceID  string `json:"invoiceID"`  // Optional
-		CustomerID string `json:"customerID"` // Optional
-		CredTranID string `json:"credTranID"` // Optional
-		LoyaltyID  string `json:"loyaltyID"`  // Optional
-		NavCheckID string `json:"navCheckID"` // Optional
-	}
-
-	err := json.NewDecoder(r.Body).Decode(&input)
+	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		sendError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	if input.PNGSize == 0 {
-		sendError(w, http.StatusBadRequest, errInvalidSize)
-		return
-	}
-
-	var c *qr.Code
-	switch input.Kind {
-	case string(qr.KindRTP):
-		c, err = qr.NewPaymentRequest(input.BIC, input.Name, input.IBAN)
-	case string(qr.KindHCT):
-		c, err = qr.NewPaymentSend(input.BIC, input.Name, input.IBAN)
-	default:
-		sendError(w, http.StatusBadRequest, errInvalidKind)
-		return
-	}
+	var input generateInput
+	err = json.Unmarshal(body, &input)
 	if err != nil {
 		sendError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	// Set all the fields
-	err = c.ValidUntil(time.Now().Add(time.Second * time.Duration(input.Expire)))
+	// The code fields are coming from the field list of the format
+	var values map[string]json.RawMessage
+	err = json.Unmarshal(body, &values)
 	if err != nil {
 		sendError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	if input.Amount > 0 {
-		err = c.HUFAmount(input.Amount)
-		if err != nil {
-			sendError(w, http.StatusBadRequest, err)
-			return
-		}
+	if input.PNGSize == 0 {
+		sendError(w, http.StatusBadRequest, errInvalidSize)
+		return
 	}
 
-	if input.Purpose != "" {
-		err = c.Purpose(input.Purpose)
-		if err != nil {
-			sendError(w, http.StatusBadRequest, err)
-			return
-		}
+	f, ok := qr.LookupFormat(input.Kind)
+	if !ok {
+		sendError(w, http.StatusBadRequest, errInvalidKind)
+		return
 	}
 
-	err = c.Message(input.Message)
+	p, err := newPayload(f, input, values)
 	if err != nil {
 		sendError(w, http.StatusBadRequest, err)
 		return
 	}
 
-	err = c.ShopID(input.ShopID)
-	if err != nil {
-		sendError(w, http.StatusBadRequest, err)
+	sendImage(w, f, p, input.imageInput)
+}
+
+// sendImage streams the image with disabled cache
+func sendImage(w http.ResponseWriter, f qr.Format, p qr.Payload, input imageInput) {
+	var err error
+	iw := &imageWriter{w: w}
+	opts := qr.RenderOptions{Size: input.PNGSize, Verify: input.Verify == nil || *input.Verify}
+	switch input.Format {
+	case "", "png":
+		iw.contentType = "image/png"
+		err = qr.WritePNG(iw, f, p, opts)
+	case "svg":
+		iw.contentType = "image/svg+xml"
+		err = qr.WriteSVG(iw, f, p, opts)
+	default:
+		sendError(w, http.StatusBadRequest, errInvalidFormat)
 		return
 	}
-
-	err = c.MerchDevID(input.MerchDevID)
-	if err != nil {
+	if err != nil && !iw.started {
 		sendError(w, http.StatusBadRequest, err)
-		return
 	}
+}
 
-	err = c.InvoiceID(input.InvoiceID)
-	if err != nil {
-		sendError(w, http.StatusBadRequest, err)
-		return
+// imageWriter sets the image headers on the first write, so the errors before that could be sent as JSON
+type imageWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	started     bool
+}
+
+// Write .
+func (iw *imageWriter) Write(p []byte) (int, error) {
+	if !iw.started {
+		iw.started = true
+		iw.w.Header().Add("Content-Type", iw.contentType)
+		iw.w.Header().Add("Cache-Control", "no-cache, no-store, must-revalidate")
+		iw.w.Header().Add("Pragma", "no-cache")
+		iw.w.Header().Add("Expires", "0")
 	}
+	return iw.w.Write(p)
+}
 
-	err = c.CustomerID(input.CustomerID)
-	if err != nil {
-		sendError(w, http.StatusBadRequest, err)
-		return
+// generateInput holds the request options, the code fields are set from the field list of the format
+type generateInput struct {
+	Kind   string `json:"kind"`   // Format name: HCT/RTP/EPC
+	Expire int    `json:"expire"` // Expire (duration) in seconds, only for the formats with expiration
+	imageInput
+}
+
+// imageInput holds the image options
+type imageInput struct {
+	PNGSize int    `json:"pngSize"` // Size in pixel
+	Format  string `json:"format"`  // Optional, png (default) or svg
+	Verify  *bool  `json:"verify"`  // Optional, decode the rendered image before sending it, true by default
+}
+
+// validUntil is implemented by the formats with expiration
+type validUntil interface {
+	ValidUntil(t time.Time) error
+}
+
+// newPayload creates the code of the format from the input
+func newPayload(f qr.Format, input generateInput, values map[string]json.RawMessage) (qr.Payload, error) {
+	p := f.New()
+	if err := setFields(f.Fields(), p, values); err != nil {
+		return nil, err
 	}
 
-	err = c.CredTranID(input.CredTranID)
-	if err != nil {
-		sendError(w, http.StatusBadRequest, err)
-		return
+	// The expire is used unless the validity is given as a field
+	if v, ok := p.(validUntil); ok && len(values["valid"]) == 0 {
+		err := v.ValidUntil(time.Now().Add(time.Second * time.Duration(input.Expire)))
+		if err != nil {
+			return nil, err
+		}
 	}
+	return p, nil
+}
 
-	err = c.CustomerID(input.CustomerID)
-	if err != nil {
-		sendError(w, http.StatusBadRequest, err)
-		return
+// setFields sets the fields which are present in the input, the kind is not set as it selects the format
+// The required fields without a value and a default are set empty, so the format could report them.
+func setFields(fields []qr.Field, p qr.Payload, values map[string]json.RawMessage) error {
+	for _, f := range fields {
+		if f.Name == "kind" {
+			continue
+		}
+
+		value, err := rawValue(values[f.Name])
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", f.Name, err)
+		}
+		if value == "" && (!f.Required || p.Get(f.Name) != "") {
+			continue
+		}
+
+		err = p.Set(f.Name, value)
+		if err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	err = c.NavCheckID(input.NavCheckID)
-	if err != nil {
-		sendError(w, http.StatusBadRequest, err)
-		return
+// rawValue returns the JSON string or number as a plain string
+func rawValue(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
 	}
 
-	// Generate the image
-	b, err := c.GeneratePNG(input.PNGSize)
-	if err != nil {
-		sendError(w, http.StatusBadRequest, err)
-		return
+	if raw[0] == '"' {
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
 	}
 
-	// Display the image with disabled cache
-	w.Header().Add("Content-Type", "image/png")
-	w.Header().Add("Cache-Control", "no-cache, no-store, must-revalidate")
-	w.Header().Add("Pragma", "no-cache")
-	w.Header().Add("Expires", "0")
-	_, _ = w.Write(b)
+	var n json.Number
+	err := json.Unmarshal(raw, &n)
+	return n.String(), err
 }
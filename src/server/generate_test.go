@@ -1,6 +1,7 @@
 package server
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
 )
 
 func TestInvalidMethod(t *testing.T) {
@@ -63,7 +66,7 @@ func TestInvalidExpiration(t *testing.T) {
 }
 
 func TestMinimalGenSuccess(t *testing.T) {
-	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":5,"kind":"HCT","bic":"abcdefgh","name":"Test User","iban":"HU00123456789012345678901234","expire":20}`))
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":128,"kind":"HCT","bic":"abcdefgh","name":"Test User","iban":"HU00123456789012345678901234","expire":20}`))
 	resp := httptest.NewRecorder()
 	New().GenerateHandler(resp, req)
 
@@ -71,3 +74,203 @@ func TestMinimalGenSuccess(t *testing.T) {
 	assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
 	assert.True(t, resp.Body.Len() > 100)
 }
+
+func TestUnreadableImage(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":5,"kind":"HCT","bic":"abcdefgh","name":"Test User","iban":"HU00123456789012345678901234","expire":20}`))
+	resp := httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "image size 5 is too small")
+
+	// The verification could be disabled, the encoder enlarges the image
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":5,"verify":false,"kind":"HCT","bic":"abcdefgh","name":"Test User","iban":"HU00123456789012345678901234","expire":20}`))
+	resp = httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+}
+
+func TestOptionalFieldErrors(t *testing.T) {
+	testTable := []struct {
+		extra       string
+		expectedErr string
+	}{
+		{`"loyaltyID":"` + strings.Repeat("a", 36) + `"`, "loyaltyID is too long"},
+		{`"customerID":"` + strings.Repeat("a", 36) + `"`, "customerID is too long"},
+		{`"amount":-5`, "amount could not be negative"},
+		{`"amount":true`, "invalid amount: json: cannot unmarshal bool into Go value of type json.Number"},
+		{`"purpose":"abcd"`, "invalid purpose code"},
+	}
+
+	for _, tt := range testTable {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":5,"kind":"HCT","bic":"abcdefgh","name":"Test User","iban":"HU00123456789012345678901234","expire":20,`+tt.extra+`}`))
+		resp := httptest.NewRecorder()
+		New().GenerateHandler(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, string(genErrorJSON(http.StatusBadRequest, errors.New(tt.expectedErr))), resp.Body.String())
+	}
+}
+
+func TestFullGenSuccess(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":128,"kind":"RTP","bic":"abcdefgh","name":"Test User","iban":"HU00123456789012345678901234","expire":20,
+		"amount":500,"purpose":"ACCT","message":"hello","shopID":"shop","merchDevID":"dev","invoiceID":"inv","customerID":"cust","credTranID":"tran","loyaltyID":"loy","navCheckID":"nav"}`))
+	resp := httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+}
+
+func TestRawValue(t *testing.T) {
+	v, err := rawValue(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", v)
+
+	v, err = rawValue([]byte(`"text"`))
+	assert.NoError(t, err)
+	assert.Equal(t, "text", v)
+
+	v, err = rawValue([]byte(`123`))
+	assert.NoError(t, err)
+	assert.Equal(t, "123", v)
+}
+
+func TestSVGGenSuccess(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":256,"format":"svg","kind":"HCT","bic":"abcdefgh","name":"Test User","iban":"HU00123456789012345678901234","expire":20}`))
+	resp := httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/svg+xml", resp.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache, no-store, must-revalidate", resp.Header().Get("Cache-Control"))
+	assert.True(t, strings.HasPrefix(resp.Body.String(), "<svg"))
+}
+
+func TestInvalidFormat(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":256,"format":"gif","kind":"HCT","bic":"abcdefgh","name":"Test User","iban":"HU00123456789012345678901234","expire":20}`))
+	resp := httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, string(genErrorJSON(http.StatusBadRequest, errInvalidFormat)), resp.Body.String())
+}
+
+func TestGenTooLarge(t *testing.T) {
+	long := strings.Repeat("a", 35)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":256,"kind":"HCT","bic":"abcdefgh","name":"`+strings.Repeat("a", 70)+`","iban":"HU00123456789012345678901234","expire":20,
+		"message":"`+strings.Repeat("a", 70)+`","shopID":"`+long+`","merchDevID":"`+long+`","invoiceID":"`+long+`","customerID":"`+long+`","credTranID":"`+long+`","loyaltyID":"`+long+`"}`))
+	resp := httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Empty(t, resp.Header().Get("Cache-Control"))
+}
+
+func TestEPCGenSuccess(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":128,"kind":"EPC","bic":"BFSWDE33BER","name":"Wikimedia Foerdergesellschaft","iban":"DE33100205000001194700","amount":123.45,"text":"Spende"}`))
+	resp := httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+}
+
+func TestEPCGenErrors(t *testing.T) {
+	testTable := []struct {
+		input       string
+		expectedErr string
+	}{
+		{`{"pngSize":128,"kind":"EPC","name":"Test User","iban":"DE33100205000001194701"}`, "invalid IBAN checksum"},
+		{`{"pngSize":128,"kind":"EPC","name":"Test User","iban":"DE33100205000001194700","amount":0.001}`, "invalid amount"},
+		{`{"pngSize":128,"kind":"EPC","name":"Test User","iban":"DE33100205000001194700","text":"a","reference":"b"}`, "reference and text could not be used together"},
+		{`{"pngSize":128,"kind":"EPC","name":"","iban":"DE33100205000001194700"}`, "name is required"},
+		{`{"pngSize":128,"kind":"EPC","version":"003","name":"Test User","iban":"DE33100205000001194700"}`, "invalid version"},
+		{`{"pngSize":128,"kind":"EPC","charset":9,"name":"Test User","iban":"DE33100205000001194700"}`, "invalid charset"},
+	}
+
+	for _, tt := range testTable {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.input))
+		resp := httptest.NewRecorder()
+		New().GenerateHandler(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, string(genErrorJSON(http.StatusBadRequest, errors.New(tt.expectedErr))), resp.Body.String())
+	}
+}
+
+func TestNewPayloadRequiredFields(t *testing.T) {
+	f, _ := qr.LookupFormat("EPC")
+	var values map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal([]byte(`{"kind":"EPC","version":"001","charset":2,"bic":"BFSWDE33BER","name":"Test User","iban":"DE33100205000001194700"}`), &values))
+
+	p, err := newPayload(f, generateInput{}, values)
+	assert.NoError(t, err)
+	assert.Equal(t, "001", p.Get("version"))
+	assert.Equal(t, "2", p.Get("charset"))
+	assert.Equal(t, "BFSWDE33BER", p.Get("bic"))
+
+	// The validity field is used instead of the expire
+	f, _ = qr.LookupFormat("HCT")
+	assert.NoError(t, json.Unmarshal([]byte(`{"kind":"HCT","bic":"OTPVHUHB","name":"Test User","iban":"HU42117730161111101800000000","valid":"20991231235959+1"}`), &values))
+	p, err = newPayload(f, generateInput{Expire: 60}, values)
+	assert.NoError(t, err)
+	assert.Equal(t, "20991231235959+1", p.Get("valid"))
+}
+
+func TestKindCaseInsensitive(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":128,"kind":"hct","bic":"abcdefgh","name":"Test User","iban":"HU00123456789012345678901234","expire":20}`))
+	resp := httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "invalid kind (should be one of EPC, HCT, PAYBYSQUARE, RTP, SPAYD)", errInvalidKind.Error())
+}
+
+func TestSPAYDGenSuccess(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":128,"kind":"SPAYD","iban":"CZ5855000000001265098001","amount":480.5,"currency":"CZK","message":"PLATBA ZA ZBOZI","format":"svg"}`))
+	resp := httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/svg+xml", resp.Header().Get("Content-Type"))
+}
+
+func TestSPAYDGenErrors(t *testing.T) {
+	testTable := []struct {
+		input       string
+		expectedErr string
+	}{
+		{`{"pngSize":128,"kind":"SPAYD"}`, "invalid IBAN length"},
+		{`{"pngSize":128,"kind":"SPAYD","iban":"CZ5855000000001265098001","currency":"czk"}`, "invalid currency"},
+		{`{"pngSize":128,"kind":"SPAYD","iban":"CZ5855000000001265098001","variableSymbol":"ABC"}`, "invalid variableSymbol"},
+	}
+
+	for _, tt := range testTable {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.input))
+		resp := httptest.NewRecorder()
+		New().GenerateHandler(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, string(genErrorJSON(http.StatusBadRequest, errors.New(tt.expectedErr))), resp.Body.String())
+	}
+}
+
+func TestPayBySquareGenSuccess(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":128,"kind":"PAYBYSQUARE","bic":"TATRSKBX","name":"Jan Novak","iban":"SK3112000000198742637541","amount":25.3,"variableSymbol":"123"}`))
+	resp := httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"pngSize":128,"kind":"PAYBYSQUARE","iban":"SK3112000000198742637541"}`))
+	resp = httptest.NewRecorder()
+	New().GenerateHandler(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, string(genErrorJSON(http.StatusBadRequest, errors.New("name is required"))), resp.Body.String())
+}
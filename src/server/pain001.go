@@ -0,0 +1,75 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/iso20022"
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+type pain001Input struct {
+	MessageID string    `json:"messageId"`
+	Version   string    `json:"version"`   // 03 (default) or 09
+	Execution string    `json:"execution"` // 2006-01-02, today by default
+	Debtor    party     `json:"debtor"`
+	Codes     []qr.Code `json:"codes"` // HCT code payloads
+}
+
+type party struct {
+	Name string `json:"name"`
+	IBAN string `json:"iban"`
+	BIC  string `json:"bic"`
+}
+
+// Pain001Handler exports the HCT codes to a pain.001 credit transfer XML
+func (s *Srv) Pain001Handler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendError(w, http.StatusMethodNotAllowed, errors.New("invalid method"))
+		return
+	}
+
+	var input pain001Input
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	version, err := iso20022.LookupPain001Version(input.Version)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	batch := iso20022.Batch{
+		MessageID: input.MessageID,
+		Version:   version,
+		Debtor:    iso20022.Party{Name: input.Debtor.Name, IBAN: input.Debtor.IBAN, BIC: input.Debtor.BIC},
+	}
+	if input.Execution != "" {
+		batch.Execution, err = time.Parse("2006-01-02", input.Execution)
+		if err != nil {
+			sendError(w, http.StatusBadRequest, errors.New("invalid execution date"))
+			return
+		}
+	}
+
+	transfers := make([]iso20022.Transfer, 0, len(input.Codes))
+	for _, c := range input.Codes {
+		transfers = append(transfers, iso20022.Transfer{Code: c})
+	}
+
+	b, err := iso20022.Pain001(transfers, batch)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", input.MessageID+".xml"))
+	_, _ = w.Write(b)
+}
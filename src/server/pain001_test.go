@@ -0,0 +1,73 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+	"github.com/stretchr/testify/assert"
+)
+
+func genPain001Body(t *testing.T, version, execution string) string {
+	c, err := qr.NewPaymentSend("OTPVHUHB", "Shop Ltd", "HU42117730161111101800000000")
+	assert.NoError(t, err)
+	assert.NoError(t, c.HUFAmount(1500))
+	assert.NoError(t, c.ValidUntil(time.Now().Add(time.Hour)))
+
+	b, err := json.Marshal(map[string]interface{}{
+		"messageId": "B1",
+		"version":   version,
+		"execution": execution,
+		"debtor":    map[string]string{"name": "Corp Ltd", "iban": "HU93116000060000000012345676", "bic": "CIBHHUHBXXX"},
+		"codes":     []*qr.Code{c, c},
+	})
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestPain001InvalidMethod(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/pain001", nil)
+	resp := httptest.NewRecorder()
+	New().Pain001Handler(resp, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+}
+
+func TestPain001(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/pain001", strings.NewReader(genPain001Body(t, "09", "2024-01-05")))
+	resp := httptest.NewRecorder()
+	New().Pain001Handler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/xml", resp.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="B1.xml"`, resp.Header().Get("Content-Disposition"))
+	assert.Contains(t, resp.Body.String(), "pain.001.001.09")
+	assert.Contains(t, resp.Body.String(), "<CtrlSum>3000</CtrlSum>")
+	assert.Contains(t, resp.Body.String(), "<Dt>2024-01-05</Dt>")
+}
+
+func TestPain001Errors(t *testing.T) {
+	testTable := []struct {
+		input       string
+		expectedErr string
+	}{
+		{genPain001Body(t, "02", ""), "unsupported version: 02"},
+		{genPain001Body(t, "", "tomorrow"), "invalid execution date"},
+		{`{"messageId":"B1","debtor":{"name":"Corp Ltd","iban":"HU93116000060000000012345676","bic":"CIBHHUHBXXX"}}`, "no transfer in the batch"},
+		{`{"codes":["HCT"]}`, "invalid line count: 1"},
+	}
+
+	for _, tt := range testTable {
+		req := httptest.NewRequest(http.MethodPost, "/pain001", strings.NewReader(tt.input))
+		resp := httptest.NewRecorder()
+		New().Pain001Handler(resp, req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, string(genErrorJSON(http.StatusBadRequest, errors.New(tt.expectedErr))), resp.Body.String())
+	}
+}
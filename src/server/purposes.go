@@ -0,0 +1,25 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+// PurposesHandler lists the purpose codes, the optional q query parameter filters them by a keyword
+func (s *Srv) PurposesHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		sendError(w, http.StatusMethodNotAllowed, errors.New("invalid method"))
+		return
+	}
+
+	purposes := qr.SearchPurposes(r.URL.Query().Get("q"))
+	if purposes == nil {
+		purposes = []qr.PurposeCode{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(purposes)
+}
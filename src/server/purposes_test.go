@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurposesInvalidMethod(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/purposes", nil)
+	resp := httptest.NewRecorder()
+	New().PurposesHandler(resp, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+}
+
+func TestPurposes(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/purposes", nil)
+	resp := httptest.NewRecorder()
+	New().PurposesHandler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+
+	var purposes []qr.PurposeCode
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&purposes))
+	assert.Equal(t, qr.PurposeCodes, purposes)
+}
+
+func TestPurposesSearch(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/purposes?q=villany", nil)
+	resp := httptest.NewRecorder()
+	New().PurposesHandler(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `[{"code":"ELEC","name":"Electricity bill","nameHU":"Villanyszámla","category":"Utilities"}]`, resp.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/purposes?q=nothing+like+this", nil)
+	resp = httptest.NewRecorder()
+	New().PurposesHandler(resp, req)
+	assert.Equal(t, "[]\n", resp.Body.String())
+}
@@ -0,0 +1,235 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gerifield/mnb-qr-go/src/qr"
+)
+
+var (
+	errUnknownTemplate   = errors.New("unknown template")
+	errTooManyTemplates  = errors.New("too many templates")
+	errMissingTemplateID = errors.New("missing template id")
+)
+
+// Limits of the template store, a template expires if it's not used for templateTTL
+const (
+	maxTemplates = 1000
+	templateTTL  = 24 * time.Hour
+)
+
+// templateStore keeps the templates in memory, they are lost on restart
+type templateStore struct {
+	mu        sync.Mutex
+	templates map[string]*storedTemplate
+}
+
+type storedTemplate struct {
+	template *qr.Template
+	used     time.Time
+}
+
+func newTemplateStore() *templateStore {
+	return &templateStore{templates: make(map[string]*storedTemplate)}
+}
+
+// add stores the template with a new random ID, the expired templates are removed first
+func (ts *templateStore) add(t *qr.Template) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(b)
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	now := time.Now()
+	for key, st := range ts.templates {
+		if now.Sub(st.used) > templateTTL {
+			delete(ts.templates, key)
+		}
+	}
+
+	if len(ts.templates) >= maxTemplates {
+		return "", errTooManyTemplates
+	}
+	ts.templates[id] = &storedTemplate{template: t, used: now}
+	return id, nil
+}
+
+// get returns a not expired template and extends its lifetime
+func (ts *templateStore) get(id string) (*qr.Template, bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	st, ok := ts.templates[id]
+	if !ok {
+		return nil, false
+	}
+
+	now := time.Now()
+	if now.Sub(st.used) > templateTTL {
+		delete(ts.templates, id)
+		return nil, false
+	}
+	st.used = now
+	return st.template, true
+}
+
+// remove deletes the template, it returns false if it was not found
+func (ts *templateStore) remove(id string) bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	_, ok := ts.templates[id]
+	delete(ts.templates, id)
+	return ok
+}
+
+// templateInput holds the kind and the patterns, the fixed fields are set from the qr field list
+type templateInput struct {
+	Kind     string            `json:"kind"`     // HCT or RTP
+	Patterns map[string]string `json:"patterns"` // Field name and pattern pairs, e.g. "message": "Számla {invoice}"
+}
+
+type templateResponse struct {
+	ID        string   `json:"id"`
+	Variables []string `json:"variables"`
+}
+
+type templateCodeInput struct {
+	ID        string            `json:"id"`
+	Variables map[string]string `json:"variables"`
+	Expire    int               `json:"expire"` // Expire (duration) in seconds
+	imageInput
+}
+
+// TemplateHandler creates a template from the recipient, the fixed optional fields and the patterns (POST) or deletes
+// the template given by the id query parameter (DELETE)
+func (s *Srv) TemplateHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		s.deleteTemplate(w, r)
+		return
+	default:
+		sendError(w, http.StatusMethodNotAllowed, errors.New("invalid method"))
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var input templateInput
+	err = json.Unmarshal(body, &input)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	// The fixed fields are set the same way as for the generation
+	var values map[string]json.RawMessage
+	err = json.Unmarshal(body, &values)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	c := &qr.Code{}
+	switch strings.ToUpper(input.Kind) {
+	case qr.KindHCT.String():
+		c.Kind = qr.KindHCT
+	case qr.KindRTP.String():
+		c.Kind = qr.KindRTP
+	default:
+		sendError(w, http.StatusBadRequest, errors.New("invalid kind (should be HCT or RTP)"))
+		return
+	}
+
+	err = setFields(qr.Fields, c, values)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	t, err := qr.NewTemplate(*c, input.Patterns)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	id, err := s.templates.add(t)
+	if err == errTooManyTemplates {
+		sendError(w, http.StatusServiceUnavailable, err)
+		return
+	}
+	if err != nil {
+		sendError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(templateResponse{ID: id, Variables: t.Variables()})
+}
+
+func (s *Srv) deleteTemplate(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		sendError(w, http.StatusBadRequest, errMissingTemplateID)
+		return
+	}
+
+	if !s.templates.remove(id) {
+		sendError(w, http.StatusNotFound, errUnknownTemplate)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TemplateCodeHandler generates the image of a code from a template and the variables
+func (s *Srv) TemplateCodeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		sendError(w, http.StatusMethodNotAllowed, errors.New("invalid method"))
+		return
+	}
+
+	var input templateCodeInput
+	err := json.NewDecoder(r.Body).Decode(&input)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if input.PNGSize == 0 {
+		sendError(w, http.StatusBadRequest, errInvalidSize)
+		return
+	}
+
+	t, ok := s.templates.get(input.ID)
+	if !ok {
+		sendError(w, http.StatusNotFound, errUnknownTemplate)
+		return
+	}
+
+	c, err := t.Code(input.Variables, time.Now().Add(time.Second*time.Duration(input.Expire)))
+	if err != nil {
+		sendError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	f, _ := qr.LookupFormat(c.Kind.String())
+	sendImage(w, f, c, input.imageInput)
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTemplate(t *testing.T, s *Srv, body string) (*httptest.ResponseRecorder, templateResponse) {
+	req := httptest.NewRequest(http.MethodPost, "/templates", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	s.TemplateHandler(resp, req)
+
+	var tr templateResponse
+	if resp.Code == http.StatusCreated {
+		assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &tr))
+	}
+	return resp, tr
+}
+
+func TestTemplateCode(t *testing.T) {
+	s := New()
+	resp, tr := createTemplate(t, s, `{"kind":"hct","bic":"OTPVHUHB","name":"Shop Ltd","iban":"HU42117730161111101800000000","purpose":"GDSV","shopID":"WEB",
+		"patterns":{"amount":"{amount}","invoiceID":"{invoice}","message":"Számla {invoice}"}}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.Len(t, tr.ID, 16)
+	assert.Equal(t, []string{"amount", "invoice"}, tr.Variables)
+
+	req := httptest.NewRequest(http.MethodPost, "/templates/code", strings.NewReader(`{"id":"`+tr.ID+`","variables":{"amount":"1500","invoice":"INV-1"},"expire":60,"pngSize":128}`))
+	resp = httptest.NewRecorder()
+	s.TemplateCodeHandler(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+
+	req = httptest.NewRequest(http.MethodPost, "/templates/code", strings.NewReader(`{"id":"`+tr.ID+`","variables":{"amount":"1500"},"expire":60,"pngSize":128}`))
+	resp = httptest.NewRecorder()
+	s.TemplateCodeHandler(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, string(genErrorJSON(http.StatusBadRequest, errors.New("missing variable: invoice"))), resp.Body.String())
+}
+
+func TestTemplateErrors(t *testing.T) {
+	s := New()
+	for _, tt := range []struct {
+		body string
+		err  string
+	}{
+		{`{"kind":"EPC"}`, "invalid kind (should be HCT or RTP)"},
+		{`{"kind":"HCT","bic":"OTPVHUHB","name":"Shop Ltd","iban":"HU4211"}`, "invalid IBAN length"},
+		{`{"kind":"HCT","bic":"OTPVHUHB","name":"Shop Ltd","iban":"HU42117730161111101800000000","purpose":"XXXX"}`, "invalid purpose code"},
+		{`{"kind":"HCT","bic":"OTPVHUHB","name":"Shop Ltd","iban":"HU42117730161111101800000000","patterns":{"message":"{x"}}`, "invalid message pattern: missing }"},
+	} {
+		resp, _ := createTemplate(t, s, tt.body)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, string(genErrorJSON(http.StatusBadRequest, errors.New(tt.err))), resp.Body.String())
+	}
+}
+
+func TestTemplateCodeErrors(t *testing.T) {
+	s := New()
+	for _, tt := range []struct {
+		body string
+		code int
+		err  error
+	}{
+		{`{"id":"x","expire":60}`, http.StatusBadRequest, errInvalidSize},
+		{`{"id":"x","expire":60,"pngSize":128}`, http.StatusNotFound, errUnknownTemplate},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/templates/code", strings.NewReader(tt.body))
+		resp := httptest.NewRecorder()
+		s.TemplateCodeHandler(resp, req)
+		assert.Equal(t, tt.code, resp.Code)
+		assert.Equal(t, string(genErrorJSON(tt.code, tt.err)), resp.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/templates", nil)
+	resp := httptest.NewRecorder()
+	s.TemplateHandler(resp, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+}
+
+func TestTemplateDelete(t *testing.T) {
+	s := New()
+	_, tr := createTemplate(t, s, `{"kind":"HCT","bic":"OTPVHUHB","name":"Shop Ltd","iban":"HU42117730161111101800000000"}`)
+
+	for _, tt := range []struct {
+		url  string
+		code int
+	}{
+		{"/templates", http.StatusBadRequest},
+		{"/templates?id=" + tr.ID, http.StatusNoContent},
+		{"/templates?id=" + tr.ID, http.StatusNotFound},
+	} {
+		req := httptest.NewRequest(http.MethodDelete, tt.url, nil)
+		resp := httptest.NewRecorder()
+		s.TemplateHandler(resp, req)
+		assert.Equal(t, tt.code, resp.Code, tt.url)
+	}
+}
+
+func TestTemplateStoreLimits(t *testing.T) {
+	s := New()
+	body := `{"kind":"HCT","bic":"OTPVHUHB","name":"Shop Ltd","iban":"HU42117730161111101800000000"}`
+	_, first := createTemplate(t, s, body)
+	for i := 1; i < maxTemplates; i++ {
+		_, _ = createTemplate(t, s, body)
+	}
+
+	resp, _ := createTemplate(t, s, body)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+	assert.Equal(t, string(genErrorJSON(http.StatusServiceUnavailable, errTooManyTemplates)), resp.Body.String())
+
+	// An expired template is removed to make room for a new one
+	s.templates.templates[first.ID].used = time.Now().Add(-templateTTL - time.Second)
+	_, ok := s.templates.get(first.ID)
+	assert.False(t, ok)
+
+	resp, _ = createTemplate(t, s, body)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Len(t, s.templates.templates, maxTemplates)
+}